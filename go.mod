@@ -2,7 +2,10 @@ module daeshboard
 
 go 1.22
 
-require github.com/gen2brain/raylib-go/raylib v0.0.0-20240227114648-c3665eb9abf8
+require (
+	github.com/gen2brain/raylib-go/raylib v0.0.0-20240227114648-c3665eb9abf8
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/ebitengine/purego v0.6.1 // indirect
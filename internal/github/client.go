@@ -1,35 +1,75 @@
 package github
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 type PR struct {
+	Number    int       `json:"number"`
 	Title     string    `json:"title"`
 	HtmlURL   string    `json:"html_url"`
 	CreatedAt time.Time `json:"created_at"`
 	Draft     bool      `json:"draft"`
+	User      User      `json:"user"`
+	State     string    `json:"state"`
+	// Nil if the PR is not merged
+	MergedAt *time.Time `json:"merged_at"`
+	Head     struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
 }
 
-// Returns all open PRs for a repo, with the most recent PRs first
-func ListPRsForRepo(host, owner, repo, token string) ([]PR, error) {
-	baseUrl := baseUrlFromHost(host)
-	url := fmt.Sprintf("%s/repos/%s/%s/pulls", baseUrl, owner, repo)
-	prs, err := list[PR](url, token)
+type User struct {
+	Login string `json:"login"`
+}
+
+// A GitHub api at BaseURL, used with Token if it's not empty
+type Client struct {
+	BaseURL string
+	Token   string
+	// Uses the package level HTTPClient if nil
+	HTTPClient *http.Client
+}
+
+func (c Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return HTTPClient
+}
+
+// Returns the PRs for a repo in state, which is open, closed or all, including
+// drafts, with the most recent PRs first
+// Only the most recent page of PRs is returned unless state is open, since
+// closed PRs pile up over time
+func (c Client) ListPRs(ctx context.Context, owner, repo, state string) ([]PR, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls?state=%s", c.BaseURL, owner, repo, state)
+	var prs []PR
+	var err error
+	if state == "open" {
+		prs, err = list[PR](ctx, c, url)
+	} else {
+		prs, _, err = getPage[[]PR](ctx, c, url+"&per_page=100")
+	}
 	if err != nil {
 		return []PR{}, fmt.Errorf("Failed to list pull requests: %s", err.Error())
 	}
-
-	// Filter draft PRs
-	prs = slices.DeleteFunc(prs, func(pr PR) bool {
-		return pr.Draft
-	})
-
+	// Sorted as a copy, so that a page that getPage hands out is never
+	// reordered behind its back
+	prs = slices.Clone(prs)
 	slices.SortFunc(prs, func(a, b PR) int {
 		return -1 * a.CreatedAt.Compare(b.CreatedAt)
 	})
@@ -37,19 +77,56 @@ func ListPRsForRepo(host, owner, repo, token string) ([]PR, error) {
 }
 
 type Issue struct {
-	Title       string `json:"title"`
-	HtmlURL     string `json:"html_url"`
-	PullRequest struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	HtmlURL       string `json:"html_url"`
+	RepositoryURL string `json:"repository_url"`
+	PullRequest   struct {
 		URL string `json:"url"`
 	} `json:"pull_request"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Returns the repo of an issue as owner/name
+func (i Issue) Repo() string {
+	_, repo, _ := strings.Cut(i.RepositoryURL, "/repos/")
+	return repo
+}
+
 // Returns all open issues for a repo, with the most recent issues first
-func ListIssuesForRepo(host, owner, repo, token string) ([]Issue, error) {
-	baseUrl := baseUrlFromHost(host)
-	url := fmt.Sprintf("%s/repos/%s/%s/issues", baseUrl, owner, repo)
-	issues, err := list[Issue](url, token)
+// Only issues that have all of the labels are returned, if there are any, and
+// that were updated after since, if it's not zero
+func (c Client) ListIssues(ctx context.Context, owner, repo string, labels []string, since time.Time) ([]Issue, error) {
+	query := url.Values{}
+	if len(labels) > 0 {
+		query.Set("labels", strings.Join(labels, ","))
+	}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	issuesUrl := fmt.Sprintf("%s/repos/%s/%s/issues", c.BaseURL, owner, repo)
+	if len(query) > 0 {
+		issuesUrl += "?" + query.Encode()
+	}
+	return listIssues(ctx, c, issuesUrl)
+}
+
+// Returns all open issues for a repo that are assigned to user, with the most
+// recent issues first
+func (c Client) ListIssuesAssignedTo(ctx context.Context, owner, repo, user string) ([]Issue, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues?assignee=%s", c.BaseURL, owner, repo, user)
+	return listIssues(ctx, c, url)
+}
+
+func listIssues(ctx context.Context, c Client, url string) ([]Issue, error) {
+	issues, err := list[Issue](ctx, c, url)
+	// Repos with issues disabled respond with 410 Gone
+	if hasStatus(err, 410) {
+		return []Issue{}, ErrDisabled
+	}
+	if hasStatus(err, 404) {
+		return []Issue{}, ErrNotFound
+	}
 	if err != nil {
 		return []Issue{}, fmt.Errorf("Failed to list issues: %s", err.Error())
 	}
@@ -61,12 +138,120 @@ func ListIssuesForRepo(host, owner, repo, token string) ([]Issue, error) {
 			filteredIssues = append(filteredIssues, issue)
 		}
 	}
-	slices.SortFunc(issues, func(a, b Issue) int {
+	slices.SortFunc(filteredIssues, func(a, b Issue) int {
 		return -1 * a.CreatedAt.Compare(b.CreatedAt)
 	})
 	return filteredIssues, nil
 }
 
+// Returns the user that the token belongs to
+func (c Client) AuthenticatedUser(ctx context.Context) (User, error) {
+	user, _, err := getPage[User](ctx, c, fmt.Sprintf("%s/user", c.BaseURL))
+	if err != nil {
+		return User{}, fmt.Errorf("Failed to get the authenticated user: %s", err.Error())
+	}
+	return user, nil
+}
+
+type SearchResponse struct {
+	TotalCount int     `json:"total_count"`
+	Items      []Issue `json:"items"`
+}
+
+// Returns all open PRs where a review is requested from user, with the most
+// recent PRs first
+// Uses the token's user if user is empty
+func (c Client) ListReviewRequestedPRs(ctx context.Context, user string) ([]Issue, error) {
+	if user == "" {
+		user = "@me"
+	}
+	prs, err := c.SearchIssues(ctx, fmt.Sprintf("is:open is:pr review-requested:%s", user))
+	if err != nil {
+		return []Issue{}, fmt.Errorf("Failed to search for review requested PRs: %s", err.Error())
+	}
+	return prs, nil
+}
+
+// Returns the issues and PRs that match a search query like `is:open author:@me`,
+// with the most recent first
+func (c Client) SearchIssues(ctx context.Context, query string) ([]Issue, error) {
+	currentPage := fmt.Sprintf("%s/search/issues?q=%s&sort=created&order=desc", c.BaseURL, url.QueryEscape(query))
+	var issues []Issue
+	for currentPage != "" {
+		response, nextPage, err := getPage[SearchResponse](ctx, c, currentPage)
+		if err != nil {
+			return []Issue{}, fmt.Errorf("Failed to search for `%s`: %s", query, err.Error())
+		}
+		issues = append(issues, response.Items...)
+		currentPage = nextPage
+	}
+	return issues, nil
+}
+
+type Notification struct {
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Subject   struct {
+		Title string `json:"title"`
+		// An api url, empty for some types of notifications
+		URL string `json:"url"`
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		HtmlURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// Returns the web url of the notification's subject, or of its repo if the
+// subject has no url
+func (n Notification) HtmlURL() string {
+	_, path, found := strings.Cut(n.Subject.URL, "/repos/")
+	if !found {
+		return n.Repository.HtmlURL
+	}
+	// The api says pulls where the web says pull
+	path = strings.Replace(path, "/pulls/", "/pull/", 1)
+	return strings.TrimSuffix(n.Repository.HtmlURL, n.Repository.FullName) + path
+}
+
+// Returns the unread notifications of the token's user, with the most recently
+// updated first
+func (c Client) ListNotifications(ctx context.Context) ([]Notification, error) {
+	url := fmt.Sprintf("%s/notifications?all=false", c.BaseURL)
+	notifications, err := list[Notification](ctx, c, url)
+	if err != nil {
+		return []Notification{}, fmt.Errorf("Failed to list notifications: %s", err.Error())
+	}
+	return notifications, nil
+}
+
+type CheckRunsResponse struct {
+	TotalCount int        `json:"total_count"`
+	CheckRuns  []CheckRun `json:"check_runs"`
+}
+
+type CheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// Returns the check runs for a commit, which is where GitHub Actions and most
+// other CI systems report their results
+func (c Client) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]CheckRun, error) {
+	currentPage := fmt.Sprintf("%s/repos/%s/%s/commits/%s/check-runs?per_page=100", c.BaseURL, owner, repo, ref)
+	var runs []CheckRun
+	for currentPage != "" {
+		response, nextPage, err := getPage[CheckRunsResponse](ctx, c, currentPage)
+		if err != nil {
+			return []CheckRun{}, fmt.Errorf("Failed to list check runs for %s: %s", ref, err.Error())
+		}
+		runs = append(runs, response.CheckRuns...)
+		currentPage = nextPage
+	}
+	return runs, nil
+}
+
 type WorkflowRunsResponse struct {
 	TotalCount   int           `json:"total_count"`
 	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
@@ -78,24 +263,258 @@ type WorkflowRun struct {
 	Conclusion string    `json:"conclusion"`
 	CreatedAt  time.Time `json:"created_at"`
 	HtmlURL    string    `json:"html_url"`
+	HeadBranch string    `json:"head_branch"`
+	Event      string    `json:"event"`
+}
+
+// List the last count workflow runs for a repo
+func (c Client) ListWorkflowRuns(ctx context.Context, owner, repo string, count int) ([]WorkflowRun, error) {
+	// 100 is the maximum page size allowed by the api
+	currentPage := fmt.Sprintf("%s/repos/%s/%s/actions/runs?per_page=%d", c.BaseURL, owner, repo, min(count, 100))
+	var runs []WorkflowRun
+	for currentPage != "" && len(runs) < count {
+		response, nextPage, err := getPage[WorkflowRunsResponse](ctx, c, currentPage)
+		if isDisabledResponse(err) {
+			return []WorkflowRun{}, ErrDisabled
+		}
+		if hasStatus(err, 404) {
+			return []WorkflowRun{}, ErrNotFound
+		}
+		if err != nil {
+			return []WorkflowRun{}, fmt.Errorf("Failed to list workflow runs for %s/%s: %s", owner, repo, err.Error())
+		}
+		runs = append(runs, response.WorkflowRuns...)
+		currentPage = nextPage
+	}
+	return runs[:min(len(runs), count)], nil
 }
 
-// List the last 5 workflows for a repo
-func ListWorkflowRunsForRepo(host, owner, repo, token string) ([]WorkflowRun, error) {
-	baseUrl := baseUrlFromHost(host)
-	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs?per_page=5", baseUrl, owner, repo)
-	resp, err := get(url, token)
+type Repository struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+	Fork     bool   `json:"fork"`
+}
+
+// Returns all repos of an organization
+func (c Client) ListReposForOrg(ctx context.Context, org string) ([]Repository, error) {
+	url := fmt.Sprintf("%s/orgs/%s/repos?per_page=100", c.BaseURL, org)
+	repos, err := list[Repository](ctx, c, url)
 	if err != nil {
-		return []WorkflowRun{}, fmt.Errorf("Failed to list workflow runs for %s/%s: %s", owner, repo, err.Error())
+		return []Repository{}, fmt.Errorf("Failed to list repos for %s: %s", org, err.Error())
 	}
-	var response WorkflowRunsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return []WorkflowRun{}, fmt.Errorf("Failed to parse workflow runs response: %s", err.Error())
+	return repos, nil
+}
+
+// A repo to get with Client.ListAll
+type RepoRef struct {
+	Owner string
+	Name  string
+}
+
+// The PRs and issues of a repo
+type RepoContents struct {
+	PRs    []PR
+	Issues []Issue
+}
+
+type GraphQLOptions struct {
+	// The state of the PRs, open, closed or all
+	PRState string
+	// Only issues that have all of these labels are returned, if there are any
+	IssueLabels []string
+	// Only issues that were updated after this are returned, if it's not zero
+	IssuesSince time.Time
+}
+
+// How many repos to get in each GraphQL query, to stay below the limit for
+// how many nodes a query may return
+var graphQLBatchSize = 20
+
+// Returns the PRs and the open issues of all repos, in the same order as the
+// repos, with the most recent first
+// This takes one GraphQL request per graphQLBatchSize repos instead of one
+// request per repo, but only the 100 most recent PRs and issues of each repo
+// are returned
+func (c Client) ListAll(ctx context.Context, repos []RepoRef, opts GraphQLOptions) ([]RepoContents, error) {
+	var contents []RepoContents
+	for start := 0; start < len(repos); start += graphQLBatchSize {
+		batch := repos[start:min(len(repos), start+graphQLBatchSize)]
+		batchContents, err := c.listAllInBatch(ctx, batch, opts)
+		if err != nil {
+			return []RepoContents{}, err
+		}
+		contents = append(contents, batchContents...)
 	}
-	return response.WorkflowRuns, nil
+	return contents, nil
+}
+
+type graphQLPR struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"createdAt"`
+	IsDraft   bool       `json:"isDraft"`
+	Author    User       `json:"author"`
+	State     string     `json:"state"`
+	MergedAt  *time.Time `json:"mergedAt"`
+	HeadSHA   string     `json:"headRefOid"`
 }
 
-func baseUrlFromHost(host string) string {
+type graphQLIssue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	Labels    struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+type graphQLRepo struct {
+	PullRequests struct {
+		Nodes []graphQLPR `json:"nodes"`
+	} `json:"pullRequests"`
+	Issues struct {
+		Nodes []graphQLIssue `json:"nodes"`
+	} `json:"issues"`
+}
+
+func (c Client) listAllInBatch(ctx context.Context, repos []RepoRef, opts GraphQLOptions) ([]RepoContents, error) {
+	prFilter := ""
+	switch opts.PRState {
+	case "open":
+		prFilter = "states: OPEN, "
+	case "closed":
+		prFilter = "states: [CLOSED, MERGED], "
+	}
+	var issueFilters []string
+	if !opts.IssuesSince.IsZero() {
+		issueFilters = append(issueFilters, fmt.Sprintf("since: %q", opts.IssuesSince.UTC().Format(time.RFC3339)))
+	}
+	if len(opts.IssueLabels) > 0 {
+		var labels []string
+		for _, label := range opts.IssueLabels {
+			labels = append(labels, strconv.Quote(label))
+		}
+		issueFilters = append(issueFilters, fmt.Sprintf("labels: [%s]", strings.Join(labels, ", ")))
+	}
+	issueFilter := ""
+	if len(issueFilters) > 0 {
+		issueFilter = fmt.Sprintf("filterBy: {%s}, ", strings.Join(issueFilters, ", "))
+	}
+	var query strings.Builder
+	query.WriteString("query {\n")
+	for i, r := range repos {
+		// Each repo needs an alias, since they are all repository fields
+		fmt.Fprintf(&query, `r%d: repository(owner: %q, name: %q) {
+  pullRequests(%sfirst: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
+    nodes { number title url createdAt isDraft author { login } state mergedAt headRefOid }
+  }
+  issues(states: OPEN, %sfirst: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
+    nodes { number title url createdAt labels(first: 20) { nodes { name } } }
+  }
+}
+`, i, r.Owner, r.Name, prFilter, issueFilter)
+	}
+	query.WriteString("}")
+	var response struct {
+		Data   map[string]*graphQLRepo `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := c.graphQL(ctx, query.String(), &response); err != nil {
+		return []RepoContents{}, err
+	}
+	if len(response.Errors) > 0 {
+		return []RepoContents{}, fmt.Errorf("Got errors from GraphQL query: %s", response.Errors[0].Message)
+	}
+	contents := make([]RepoContents, len(repos))
+	for i, r := range repos {
+		repo := response.Data[fmt.Sprintf("r%d", i)]
+		if repo == nil {
+			return []RepoContents{}, fmt.Errorf("Got no data for %s/%s from GraphQL query", r.Owner, r.Name)
+		}
+		for _, node := range repo.PullRequests.Nodes {
+			pr := PR{
+				Number:    node.Number,
+				Title:     node.Title,
+				HtmlURL:   node.URL,
+				CreatedAt: node.CreatedAt,
+				Draft:     node.IsDraft,
+				User:      node.Author,
+				State:     "open",
+				MergedAt:  node.MergedAt,
+			}
+			// Merged PRs are closed in the REST api
+			if node.State != "OPEN" {
+				pr.State = "closed"
+			}
+			pr.Head.SHA = node.HeadSHA
+			contents[i].PRs = append(contents[i].PRs, pr)
+		}
+		for _, node := range repo.Issues.Nodes {
+			var labels []string
+			for _, label := range node.Labels.Nodes {
+				labels = append(labels, label.Name)
+			}
+			// The labels filter of the query matches issues with any of the
+			// labels, but all of them are wanted
+			hasAll := true
+			for _, label := range opts.IssueLabels {
+				hasAll = hasAll && slices.Contains(labels, label)
+			}
+			if !hasAll {
+				continue
+			}
+			contents[i].Issues = append(contents[i].Issues, Issue{
+				Number:        node.Number,
+				Title:         node.Title,
+				HtmlURL:       node.URL,
+				RepositoryURL: fmt.Sprintf("%s/repos/%s/%s", c.BaseURL, r.Owner, r.Name),
+				CreatedAt:     node.CreatedAt,
+			})
+		}
+	}
+	return contents, nil
+}
+
+// Runs a GraphQL query and decodes the response into output
+func (c Client) graphQL(ctx context.Context, query string, output any) error {
+	// The GraphQL api of a GitHub Enterprise server is at /api/graphql, next
+	// to the REST api at /api/v3
+	url := strings.TrimSuffix(c.BaseURL, "/v3") + "/graphql"
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return fmt.Errorf("Could not encode GraphQL query: %s", err.Error())
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Could not create POST request: %s", err.Error())
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("Failed to query %s: %s", url, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Got non-200 status code from %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("Could not parse response from %s: %s", url, err.Error())
+	}
+	return nil
+}
+
+// Returns the api base url for a host, where hosts other than github.com are
+// assumed to be GitHub Enterprise servers
+func BaseUrlFromHost(host string) string {
 	if host == "github.com" {
 		return "https://api.github.com"
 	} else {
@@ -103,7 +522,9 @@ func baseUrlFromHost(host string) string {
 	}
 }
 
-var nextPagePattern = regexp.MustCompile(`<([\S]+)>; rel="next"`)
+// The urls can't contain >, so this never matches across several links even
+// when they are not separated by spaces
+var nextPagePattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
 
 // Extracts the url to the next page from the link header
 // Returns the empty string if not found
@@ -115,39 +536,269 @@ func getNextPage(linkHeader string) string {
 	return match[1]
 }
 
-func list[T PR | Issue](url, token string) ([]T, error) {
+func list[T PR | Issue | Repository | Notification](ctx context.Context, c Client, url string) ([]T, error) {
+	maxItems := MaxItems(ctx)
 	currentPage := url
 	var allOutput []T
 	for currentPage != "" {
-		resp, err := get(currentPage, token)
+		if maxItems > 0 && len(allOutput) >= maxItems {
+			return allOutput[:maxItems], nil
+		}
+		output, nextPage, err := getPage[[]T](ctx, c, currentPage)
 		if err != nil {
 			return []T{}, err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 {
-			return []T{}, fmt.Errorf("Got non-200 status code: %s", resp.Status)
-		}
-		var output []T
-		if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
-			return []T{}, fmt.Errorf("Could not parse response: %s", err.Error())
-		}
 		allOutput = append(allOutput, output...)
-		currentPage = getNextPage(resp.Header.Get("Link"))
+		currentPage = nextPage
 	}
 	return allOutput, nil
 }
 
-func get(url, token string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+type maxItemsKey struct{}
+
+// Returns a context that makes the requests made with it stop following the
+// next pages once they have at least n items, there is no limit if n is zero
+func WithMaxItems(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxItemsKey{}, n)
+}
+
+// Returns the limit set with WithMaxItems, or zero if there is none
+func MaxItems(ctx context.Context) int {
+	n, _ := ctx.Value(maxItemsKey{}).(int)
+	return n
+}
+
+// Returned instead of an error when issues or actions are disabled for a repo
+var ErrDisabled = errors.New("Disabled for the repo")
+
+// Returned instead of an error when the issues or actions of a repo are not
+// found, which is also the case when the token can not see the repo
+var ErrNotFound = errors.New("Not found, or not visible with the token")
+
+// A response that did not have status code 200
+type StatusError struct {
+	URL        string
+	Status     string
+	StatusCode int
+	// Why the request failed, from the body of the response
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Got non-200 status code from %s: %s: %s", e.URL, e.Status, e.Message)
+	}
+	return fmt.Sprintf("Got non-200 status code from %s: %s", e.URL, e.Status)
+}
+
+// Returns true if err is a response with one of the status codes
+func hasStatus(err error, codes ...int) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && slices.Contains(codes, statusErr.StatusCode)
+}
+
+// Returns true if err is a response that says that the feature is disabled for
+// the repo, like "Actions is disabled for this repository"
+func isDisabledResponse(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && strings.Contains(strings.ToLower(statusErr.Message), "disabled")
+}
+
+// Returns the decoded response for a page and the url to the next page
+func getPage[R any](ctx context.Context, c Client, url string) (R, string, error) {
+	var output R
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return output, "", err
+	}
+	defer resp.Body.Close()
+	key := cacheKey{token: c.Token, url: url}
+	if resp.StatusCode == 304 {
+		entry, ok := cache.get(key)
+		if !ok {
+			return output, "", fmt.Errorf("Got 304 Not Modified without a cached response for %s", url)
+		}
+		if err := json.Unmarshal(entry.body, &output); err != nil {
+			return output, "", fmt.Errorf("Could not parse cached response from %s: %s", url, err.Error())
+		}
+		return output, entry.nextPage, nil
+	}
+	if resp.StatusCode != 200 {
+		statusErr := &StatusError{URL: url, Status: resp.Status, StatusCode: resp.StatusCode}
+		var body struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil {
+			statusErr.Message = body.Message
+		}
+		return output, "", statusErr
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return output, "", fmt.Errorf("Could not read response from %s: %s", url, err.Error())
+	}
+	if err := json.Unmarshal(body, &output); err != nil {
+		return output, "", fmt.Errorf("Could not parse response from %s: %s", url, err.Error())
+	}
+	nextPage := getNextPage(resp.Header.Get("Link"))
+	cache.set(key, cacheEntry{etag: resp.Header.Get("ETag"), body: body, nextPage: nextPage})
+	return output, nextPage, nil
+}
+
+// Responses depend on who asks for them, so the same url is cached once per
+// token
+type cacheKey struct {
+	token string
+	url   string
+}
+
+type cacheEntry struct {
+	etag string
+	// Decoded again on every hit, so that callers can modify what they get
+	// without changing the cached response
+	body     []byte
+	nextPage string
+	// When the entry was last set or looked up, to evict the least recently
+	// used entries first
+	usedAt time.Time
+}
+
+// Responses from previous requests, keyed by token and url
+// Used to make conditional requests with If-None-Match, which GitHub does not
+// count against the rate limit when answered with 304 Not Modified
+// Holds at most maxCacheEntries, since urls like the ones for the check runs
+// of a commit are not requested again once the commit is old
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+// How many responses the cache holds before it evicts the least recently used
+var maxCacheEntries = 1000
+
+var cache = responseCache{entries: map[cacheKey]cacheEntry{}}
+
+func (c *responseCache) get(key cacheKey) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok {
+		entry.usedAt = time.Now()
+		c.entries[key] = entry
+	}
+	return entry, ok
+}
+
+func (c *responseCache) set(key cacheKey, entry cacheEntry) {
+	if entry.etag == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.usedAt = time.Now()
+	c.entries[key] = entry
+	for len(c.entries) > maxCacheEntries {
+		oldest := key
+		for k, e := range c.entries {
+			if e.usedAt.Before(c.entries[oldest].usedAt) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// The client used for the requests of a Client without an HTTPClient
+var HTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+func (c Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Could not create GET request: %s", err.Error())
 	}
-	if token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	if c.Token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
 	}
-	resp, err := http.DefaultClient.Do(req)
+	if entry, ok := cache.get(cacheKey{token: c.Token, url: url}); ok {
+		req.Header.Add("If-None-Match", entry.etag)
+	}
+	return c.do(ctx, req)
+}
+
+// Makes the request, retrying it if it fails in a way that is likely transient
+func (c Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("Could not reset the request body: %s", err.Error())
+			}
+			req.Body = body
+		}
+		resp, err := c.httpClient().Do(req)
+		if err == nil {
+			rateLimit.update(resp.Header)
+		}
+		// Network errors, rate limiting and server errors are usually transient
+		retryable := (err != nil && ctx.Err() == nil) || (err == nil && (resp.StatusCode == 429 || resp.StatusCode >= 500))
+		if !retryable || attempt == len(retryDelays) {
+			if err != nil {
+				return nil, fmt.Errorf("Failed to make request: %s", err.Error())
+			}
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Failed to make request: %s", ctx.Err().Error())
+		case <-time.After(retryDelays[attempt]):
+		}
+	}
+}
+
+// How long to wait before each retry of a failed request
+var retryDelays = []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second}
+
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// The rate limit from the most recent response that had one
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	value RateLimit
+	known bool
+}
+
+var rateLimit = rateLimitTracker{}
+
+func (t *rateLimitTracker) update(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
 	if err != nil {
-		return nil, fmt.Errorf("Failed to make request: %s", err.Error())
+		return
 	}
-	return resp, nil
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.value = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.known = true
+}
+
+// Returns the rate limit from the most recent response, and false if no
+// response has had one yet
+func GetRateLimit() (RateLimit, bool) {
+	rateLimit.mu.Lock()
+	defer rateLimit.mu.Unlock()
+	return rateLimit.value, rateLimit.known
 }
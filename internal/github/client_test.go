@@ -0,0 +1,247 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetNextPage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "next first",
+			header: `<https://api.github.com/repos/o/r/pulls?page=2>; rel="next", <https://api.github.com/repos/o/r/pulls?page=5>; rel="last"`,
+			want:   "https://api.github.com/repos/o/r/pulls?page=2",
+		},
+		{
+			name:   "next between the others",
+			header: `<https://api.github.com/repos/o/r/pulls?page=1>; rel="prev", <https://api.github.com/repos/o/r/pulls?page=3>; rel="next", <https://api.github.com/repos/o/r/pulls?page=5>; rel="last", <https://api.github.com/repos/o/r/pulls?page=1>; rel="first"`,
+			want:   "https://api.github.com/repos/o/r/pulls?page=3",
+		},
+		{
+			name:   "next last with extra params",
+			header: `<https://api.github.com/repos/o/r/pulls?page=1&per_page=100>; rel="first", <https://api.github.com/repos/o/r/pulls?page=4&per_page=100&state=all>; rel="next"`,
+			want:   "https://api.github.com/repos/o/r/pulls?page=4&per_page=100&state=all",
+		},
+		{
+			name:   "no separating spaces",
+			header: `<https://api.github.com/repos/o/r/pulls?page=1>;rel="prev",<https://api.github.com/repos/o/r/pulls?page=3>;rel="next"`,
+			want:   "https://api.github.com/repos/o/r/pulls?page=3",
+		},
+		{
+			name:   "next absent on the last page",
+			header: `<https://api.github.com/repos/o/r/pulls?page=4>; rel="prev", <https://api.github.com/repos/o/r/pulls?page=1>; rel="first"`,
+			want:   "",
+		},
+		{
+			name:   "malformed",
+			header: `https://api.github.com/repos/o/r/pulls?page=2; rel=next`,
+			want:   "",
+		},
+		{
+			name:   "empty",
+			header: "",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getNextPage(tt.header); got != tt.want {
+				t.Errorf("getNextPage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+// Makes the retries in the test immediate, with as many of them as before
+func withShortRetryDelays(t *testing.T) {
+	previous := retryDelays
+	retryDelays = make([]time.Duration, len(previous))
+	t.Cleanup(func() { retryDelays = previous })
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	withShortRetryDelays(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `[{"number": 1, "title": "Fix it"}]`)
+	}))
+	defer server.Close()
+
+	prs, err := Client{BaseURL: server.URL}.ListPRs(context.Background(), "owner", "repo", "open")
+	if err != nil {
+		t.Fatalf("Got error after the server recovered: %s", err.Error())
+	}
+	if requests != 3 {
+		t.Errorf("Made %d requests, want 3", requests)
+	}
+	if len(prs) != 1 || prs[0].Number != 1 {
+		t.Errorf("Got PRs %+v, want the one PR from the last response", prs)
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	withShortRetryDelays(t)
+	for _, status := range []int{http.StatusUnauthorized, http.StatusNotFound} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			_, err := Client{BaseURL: server.URL}.ListPRs(context.Background(), "owner", "repo", "open")
+			if err == nil {
+				t.Errorf("Got no error for status %d", status)
+			}
+			if requests != 1 {
+				t.Errorf("Made %d requests, want 1", requests)
+			}
+		})
+	}
+}
+
+func TestListIssuesLeavesOutPRsAndSortsNewestFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"number": 1, "title": "Old issue", "created_at": "2024-01-01T00:00:00Z"},
+			{"number": 2, "title": "PR", "created_at": "2024-03-01T00:00:00Z", "pull_request": {"url": "https://api.github.com/repos/o/r/pulls/2"}},
+			{"number": 3, "title": "New issue", "created_at": "2024-04-01T00:00:00Z"},
+			{"number": 4, "title": "Middle issue", "created_at": "2024-02-01T00:00:00Z"},
+			{"number": 5, "title": "Old PR", "created_at": "2023-12-01T00:00:00Z", "pull_request": {"url": "https://api.github.com/repos/o/r/pulls/5"}}
+		]`)
+	}))
+	defer server.Close()
+
+	issues, err := Client{BaseURL: server.URL}.ListIssues(context.Background(), "o", "r", nil, time.Time{})
+	if err != nil {
+		t.Fatalf("Could not list issues: %s", err.Error())
+	}
+	var numbers []int
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Number)
+	}
+	if want := []int{3, 4, 1}; !slices.Equal(numbers, want) {
+		t.Errorf("Got issues %v, want %v", numbers, want)
+	}
+}
+
+func TestCachedResponsesArePerTokenAndNotShared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The same etag for both tokens, so only the key of the cache keeps
+		// them apart
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("Authorization") == "Bearer a" {
+			fmt.Fprint(w, `[{"number": 1, "title": "Seen by a"}]`)
+		} else {
+			fmt.Fprint(w, `[{"number": 2, "title": "Seen by b"}]`)
+		}
+	}))
+	defer server.Close()
+
+	a := Client{BaseURL: server.URL, Token: "a"}
+	b := Client{BaseURL: server.URL, Token: "b"}
+	for _, c := range []Client{a, b, a, b} {
+		prs, err := c.ListPRs(context.Background(), "o", "r", "all")
+		if err != nil {
+			t.Fatalf("Could not list PRs: %s", err.Error())
+		}
+		want := map[string]int{"a": 1, "b": 2}[c.Token]
+		if len(prs) != 1 || prs[0].Number != want {
+			t.Fatalf("Got PRs %+v with token %s, want only #%d", prs, c.Token, want)
+		}
+		// Must not change what the next 304 Not Modified returns
+		prs[0].Number = 0
+	}
+}
+
+func TestListPRsSortsNewestFirstOnEveryHit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[
+			{"number": 1, "title": "Old", "created_at": "2024-01-01T00:00:00Z"},
+			{"number": 2, "title": "New", "created_at": "2024-03-01T00:00:00Z"}
+		]`)
+	}))
+	defer server.Close()
+
+	for range 2 {
+		prs, err := Client{BaseURL: server.URL}.ListPRs(context.Background(), "o", "r", "closed")
+		if err != nil {
+			t.Fatalf("Could not list PRs: %s", err.Error())
+		}
+		var numbers []int
+		for _, pr := range prs {
+			numbers = append(numbers, pr.Number)
+		}
+		if want := []int{2, 1}; !slices.Equal(numbers, want) {
+			t.Fatalf("Got PRs %v, want %v", numbers, want)
+		}
+	}
+}
+
+func TestDisabledAndMissingRepos(t *testing.T) {
+	listIssues := func(c Client) error {
+		_, err := c.ListIssues(context.Background(), "o", "r", nil, time.Time{})
+		return err
+	}
+	listRuns := func(c Client) error {
+		_, err := c.ListWorkflowRuns(context.Background(), "o", "r", 10)
+		return err
+	}
+	tests := []struct {
+		name   string
+		list   func(c Client) error
+		status int
+		body   string
+		// Nil for an error that is neither ErrDisabled nor ErrNotFound
+		wantErr error
+	}{
+		{"issues disabled", listIssues, http.StatusGone, `{"message": "Issues are disabled for this repo"}`, ErrDisabled},
+		{"issues not found", listIssues, http.StatusNotFound, `{"message": "Not Found"}`, ErrNotFound},
+		{"issues forbidden", listIssues, http.StatusForbidden, `{"message": "Resource not accessible by integration"}`, nil},
+		{"actions disabled", listRuns, http.StatusForbidden, `{"message": "Actions is disabled for this repository"}`, ErrDisabled},
+		{"actions not found", listRuns, http.StatusNotFound, `{"message": "Not Found"}`, ErrNotFound},
+		{"actions forbidden", listRuns, http.StatusForbidden, `{"message": "Resource not accessible by integration"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			err := tt.list(Client{BaseURL: server.URL})
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (err == nil || errors.Is(err, ErrDisabled) || errors.Is(err, ErrNotFound)) {
+				t.Errorf("Got error %v, want a plain error", err)
+			}
+		})
+	}
+}
@@ -1,112 +1,518 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"daeshboard/internal/github"
+	"gopkg.in/yaml.v3"
 )
 
 var (
 	WINDOW_WIDTH   = 1000
 	WINDOW_HEIGHT  = 450
+	HEADER_Y       = 10
 	RULER_Y        = 40
 	BODY_Y         = 60
 	HELP_Y_PADDING = 50
 	PAD_X          = 40
+	// Between the sections of the help text
+	HELP_SEPARATOR = "    "
+	// Between the source column and the values in the body
+	SOURCE_GAP = 20
+
+	// Small enough to tile, large enough for the help text in two lines
+	MIN_WINDOW_WIDTH  = 500
+	MIN_WINDOW_HEIGHT = 250
 
 	FONT_SIZE_HEADER = 25
 	FONT_SIZE_BODY   = 20
 	FONT_SIZE_HELP   = 20
+	FONT_SIZE_SMALL  = 14
 
 	COLOR_BLUE_BG = rl.NewColor(91, 206, 250, 100)
 	COLOR_PINK_BG = rl.NewColor(245, 169, 184, 100)
 	COLOR_BLACK   = rl.NewColor(0, 0, 0, 255)
 	COLOR_GRAY    = rl.NewColor(150, 150, 150, 255)
+	COLOR_RED     = rl.NewColor(220, 50, 50, 255)
+	COLOR_GREEN   = rl.NewColor(40, 160, 70, 255)
+	COLOR_YELLOW  = rl.NewColor(200, 150, 0, 255)
+	COLOR_WHITE   = rl.NewColor(245, 245, 245, 255)
+	COLOR_DARK    = rl.NewColor(30, 30, 35, 255)
+	COLOR_LIGHT   = rl.NewColor(220, 220, 220, 255)
 
+	// Set by applyTheme
+	COLOR_BACKGROUND      = COLOR_WHITE
 	COLOR_HEADER          = COLOR_BLACK
 	COLOR_SELECTED_HEADER = COLOR_BLUE_BG
 	COLOR_SELECTED_ITEM   = COLOR_BLUE_BG
 	COLOR_RULER           = COLOR_GRAY
 	COLOR_ITEM            = COLOR_BLACK
 	COLOR_HELP            = COLOR_BLACK
+	COLOR_HELP_BG         = COLOR_PINK_BG
+	COLOR_ERROR           = COLOR_RED
+	COLOR_FAILURE         = COLOR_RED
+	COLOR_SUCCESS         = COLOR_GREEN
+	COLOR_IN_PROGRESS     = COLOR_YELLOW
+	COLOR_CRITICAL        = COLOR_RED
+	COLOR_MUTED           = COLOR_GRAY
+	COLOR_LONG_FIRING     = COLOR_YELLOW
 
 	PROGRAM_NAME = "Daeshboard"
+	STATE_FILE   = "daeshboard-state.json"
+	DEFAULT_FONT = "JetBrainsMonoNerdFont-Medium.ttf"
+	// The font sizes change with the config, so keep the defaults separately
+	DEFAULT_FONT_SIZES = FontSizes{Header: 25, Body: 20, Help: 20}
+	THEME_LIGHT        = "light"
+	THEME_DARK         = "dark"
+
+	DEFAULT_REFRESH_INTERVAL       = 10 * time.Second
+	DEFAULT_GITHUB_BASE_URL        = "https://api.github.com"
+	DEFAULT_HTTP_TIMEOUT           = 10 * time.Second
+	MAX_CONCURRENT_REQUESTS        = 5
+	CONFIG_POLL_INTERVAL           = 2 * time.Second
+	DEFAULT_WORKFLOW_RUNS_PER_REPO = 5
+	FAILED_CONCLUSIONS             = []string{"failure", "cancelled", "timed_out"}
+	// The titles of the tabs that are not from the config
+	BUILTIN_TABS = []string{"All", "PRs", "Reviews", "Issues", "Assigned", "Inbox", "Alerts", "Workflows"}
+	// Opening more items than this at once asks for confirmation first
+	OPEN_ALL_CONFIRM_THRESHOLD = 10
+	SPINNER_FRAMES             = "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏"
+	// How long to wait for a motion after a digit, before switching tabs
+	PENDING_COUNT_TIMEOUT = 500 * time.Millisecond
+	// Alerts that have been firing for longer than this are highlighted
+	LONG_FIRING_ALERT = 24 * time.Hour
 )
 
 type Config struct {
-	Repos        []Repo
-	Alerts       AlertsConfig
-	GithubTokens map[string]string
+	Repos           []Repo
+	Alerts          AlertsConfig
+	GithubTokens    map[string]string
+	RefreshInterval time.Duration
+	HideDraftPRs    bool
+	Keybindings     map[string][]Keybinding
+	GithubBaseURL   string
+	GithubHost      string
+	ReviewRequested bool
+	AssignedIssues  bool
+	AllTab          bool
+	Notifications   bool
+	PRChecks        bool
+	WindowWidth     int
+	WindowHeight    int
+	FontSizes       FontSizes
+	IssueLabels     []string
+	PRState         string
+	DedupItems      bool
+	IgnoreAuthors   []string
+	Font            string
+	LatestRunsOnly  bool
+	WorkflowRuns    int
+	HTTPTimeout     time.Duration
+	Theme           string
+	// Only show items from this long ago, or all items if zero
+	Since      time.Duration
+	CustomTabs []CustomTab
+	// The tabs to send notifications for, all tabs if nil
+	NotifyTabs []string
+	// How many items to get per request at first, and how many more to get
+	// when loading more, or no limit if zero
+	MaxItems int
+	// Quitting needs the quit key to be pressed twice
+	ConfirmQuit bool
+	// The icons and accent colors of the tabs, by tab title
+	TabStyles map[string]TabStyle
+	// Get the PRs and issues with GraphQL queries instead of a request per repo
+	GraphQL bool
+	// How often to fetch some of the tabs, by tab title, instead of
+	// RefreshInterval
+	TabIntervals map[string]time.Duration
+	// Leave the tabs without items out of the header
+	HideEmptyTabs bool
+	// Leave the archived or forked repos out of the repos of an organization
+	SkipArchived bool
+	SkipForks    bool
+}
+
+type TabStyle struct {
+	// Drawn in front of the title, usually a Nerd Font glyph
+	Icon string
+	// Used for the highlight when the tab is selected, COLOR_SELECTED_HEADER
+	// if not set
+	Accent rl.Color
+}
+
+// A tab with the results of a GitHub search
+type CustomTab struct {
+	Title string `json:"title" yaml:"title"`
+	Query string `json:"query" yaml:"query"`
+}
+
+type FontSizes struct {
+	Header int `json:"header" yaml:"header"`
+	Body   int `json:"body" yaml:"body"`
+	Help   int `json:"help" yaml:"help"`
 }
 
 type AlertsConfig struct {
 	Server   string
 	Receiver string
+	Username string
+	Password string
+	Token    string
+	// Label matchers like `namespace="prod"`
+	Filters []string
+}
+
+// Add credentials to a request to Alertmanager, if there are any
+func (c AlertsConfig) authorize(req *http.Request) {
+	if c.Token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	} else if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
 }
 
 type Repo struct {
-	Host  string
-	Owner string
-	Name  string
+	Host    string
+	BaseURL string
+	Owner   string
+	Name    string
+	Token   string
 }
 
 func (r Repo) String() string {
 	return fmt.Sprintf("%s/%s", r.Owner, r.Name)
 }
 
+// Returns the url of the repo on the website
+func (r Repo) webURL() string {
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Name)
+}
+
+// Returns the url of a page of the repo of a page on the website, like
+// https://github.com/owner/name/pulls for https://github.com/owner/name/pull/1
+// and /pulls, or the empty string if the url has no repo
+func repoURLOf(pageURL, page string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/%s/%s%s", u.Scheme, u.Host, parts[0], parts[1], page)
+}
+
+// Returns the token to use for the repo, falling back to the token for its host
+func (r Repo) githubToken(tokens map[string]string) string {
+	if r.Token != "" {
+		return r.Token
+	}
+	return tokens[r.Host]
+}
+
+// Returns a client for the api of the repo's host, with the repo's token
+func (r Repo) client(tokens map[string]string, httpClient *http.Client) github.Client {
+	return github.Client{BaseURL: r.BaseURL, Token: r.githubToken(tokens), HTTPClient: httpClient}
+}
+
+// A repo in the config, either a plain string like "owner/name" or an object
+// with a name and a token to use for that repo
+type repoEntry struct {
+	Name  string `json:"name" yaml:"name"`
+	Token string `json:"token" yaml:"token"`
+}
+
+func (e *repoEntry) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		e.Name = name
+		return nil
+	}
+	type plainRepoEntry repoEntry
+	return json.Unmarshal(data, (*plainRepoEntry)(e))
+}
+
+func (e *repoEntry) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&e.Name)
+	}
+	type plainRepoEntry repoEntry
+	return value.Decode((*plainRepoEntry)(e))
+}
+
+// Only ${VAR} is expanded, since a bare $ is common in passwords and regexes
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Replaces ${VAR} with the value of the environment variable VAR
+// References to variables that are not set are left as they are
+func expandEnv(contents []byte) []byte {
+	return envVarPattern.ReplaceAllFunc(contents, func(ref []byte) []byte {
+		if value, ok := os.LookupEnv(string(envVarPattern.FindSubmatch(ref)[1])); ok {
+			return []byte(value)
+		}
+		return ref
+	})
+}
+
+// Decodes the contents of a config file into config, as YAML if the file ends
+// with .yaml or .yml and as JSON otherwise
+func decodeConfig(filename string, contents []byte, config any) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(contents, config)
+	default:
+		return json.Unmarshal(contents, config)
+	}
+}
+
 func buildConfig(filename string) (Config, error) {
 	contents, err := os.ReadFile(filename)
 	if err != nil {
 		return Config{}, fmt.Errorf("Could not open file: %s", err.Error())
 	}
+	// Allow referencing secrets and such with ${VAR}
+	contents = expandEnv(contents)
 	var config struct {
-		Repos  []string `json:"repos"`
-		Alerts struct {
-			Server   string `json:"server"`
-			Receiver string `json:"receiver"`
-		} `json:"alerts"`
+		Repos  []repoEntry `json:"repos" yaml:"repos"`
+		Alerts *struct {
+			Server   string   `json:"server" yaml:"server"`
+			Receiver string   `json:"receiver" yaml:"receiver"`
+			Username string   `json:"username" yaml:"username"`
+			Password string   `json:"password" yaml:"password"`
+			Token    string   `json:"token" yaml:"token"`
+			Filters  []string `json:"filters" yaml:"filters"`
+		} `json:"alerts" yaml:"alerts"`
+		RefreshInterval string              `json:"refreshInterval" yaml:"refreshInterval"`
+		HideDraftPRs    *bool               `json:"hideDraftPRs" yaml:"hideDraftPRs"`
+		Keybindings     map[string][]string `json:"keybindings" yaml:"keybindings"`
+		GithubBaseURL   string              `json:"githubBaseURL" yaml:"githubBaseURL"`
+		GithubTokenFile string              `json:"githubTokenFile" yaml:"githubTokenFile"`
+		OwnerTokens     map[string]string   `json:"ownerTokens" yaml:"ownerTokens"`
+		ReviewRequested bool                `json:"reviewRequestedTab" yaml:"reviewRequestedTab"`
+		AssignedIssues  bool                `json:"assignedIssuesTab" yaml:"assignedIssuesTab"`
+		AllTab          bool                `json:"allTab" yaml:"allTab"`
+		Notifications   bool                `json:"notificationsTab" yaml:"notificationsTab"`
+		PRChecks        bool                `json:"prChecks" yaml:"prChecks"`
+		WindowWidth     int                 `json:"windowWidth" yaml:"windowWidth"`
+		WindowHeight    int                 `json:"windowHeight" yaml:"windowHeight"`
+		IssueLabels     []string            `json:"issueLabels" yaml:"issueLabels"`
+		SkipArchived    bool                `json:"skipArchivedRepos" yaml:"skipArchivedRepos"`
+		SkipForks       bool                `json:"skipForkedRepos" yaml:"skipForkedRepos"`
+		PRState         string              `json:"prState" yaml:"prState"`
+		DedupItems      bool                `json:"dedupItems" yaml:"dedupItems"`
+		IgnoreAuthors   []string            `json:"ignorePRAuthors" yaml:"ignorePRAuthors"`
+		Since           string              `json:"since" yaml:"since"`
+		CustomTabs      []CustomTab         `json:"tabs" yaml:"tabs"`
+		NotifyTabs      []string            `json:"notify" yaml:"notify"`
+		Font            string              `json:"font" yaml:"font"`
+		FontSizes       FontSizes           `json:"fontSizes" yaml:"fontSizes"`
+		LatestRunsOnly  bool                `json:"latestWorkflowRunsOnly" yaml:"latestWorkflowRunsOnly"`
+		WorkflowRuns    int                 `json:"workflowRunsPerRepo" yaml:"workflowRunsPerRepo"`
+		HTTPTimeout     string              `json:"httpTimeout" yaml:"httpTimeout"`
+		Theme           string              `json:"theme" yaml:"theme"`
+		MaxItems        int                 `json:"maxItems" yaml:"maxItems"`
+		ConfirmQuit     bool                `json:"confirmQuit" yaml:"confirmQuit"`
+		GraphQL         bool                `json:"graphql" yaml:"graphql"`
+		TabIntervals    map[string]string   `json:"tabRefreshIntervals" yaml:"tabRefreshIntervals"`
+		HideEmptyTabs   bool                `json:"hideEmptyTabs" yaml:"hideEmptyTabs"`
+		TabStyles       map[string]struct {
+			Icon  string `json:"icon" yaml:"icon"`
+			Color string `json:"color" yaml:"color"`
+		} `json:"tabStyles" yaml:"tabStyles"`
 	}
-	if err := json.Unmarshal(contents, &config); err != nil {
+	if err := decodeConfig(filename, contents, &config); err != nil {
 		return Config{}, fmt.Errorf("Could not parse config: %s", err.Error())
 	}
+	// The base url is used for repos that don't specify a host
+	githubBaseURL := DEFAULT_GITHUB_BASE_URL
+	if config.GithubBaseURL != "" {
+		githubBaseURL = strings.TrimSuffix(config.GithubBaseURL, "/")
+	}
+	parsedBaseURL, err := url.Parse(githubBaseURL)
+	if err != nil || parsedBaseURL.Host == "" {
+		return Config{}, fmt.Errorf("Incorrect githubBaseURL, should be an absolute url like https://github.mycompany.com/api/v3, got %s", config.GithubBaseURL)
+	}
+	defaultHost := parsedBaseURL.Hostname()
+	if githubBaseURL == DEFAULT_GITHUB_BASE_URL {
+		defaultHost = "github.com"
+	}
 	var repos []Repo
-	for _, repo := range config.Repos {
-		split := strings.Split(repo, "/")
+	for _, entry := range config.Repos {
+		var r Repo
+		split := strings.Split(entry.Name, "/")
 		switch len(split) {
 		case 2:
-			repos = append(repos, Repo{
-				Host:  "github.com",
-				Owner: split[0],
-				Name:  split[1],
-			})
+			r = Repo{
+				Host:    defaultHost,
+				BaseURL: githubBaseURL,
+				Owner:   split[0],
+				Name:    split[1],
+			}
 		case 3:
-			repos = append(repos, Repo{
-				Host:  split[0],
-				Owner: split[1],
-				Name:  split[2],
-			})
+			r = Repo{
+				Host:    split[0],
+				BaseURL: github.BaseUrlFromHost(split[0]),
+				Owner:   split[1],
+				Name:    split[2],
+			}
 		default:
-			return Config{}, fmt.Errorf("Incorrect repo format, should be `owner/name` or `host/owner/name`, got %s", repo)
+			return Config{}, fmt.Errorf("Incorrect repo format, should be `owner/name` or `host/owner/name`, got %s", entry.Name)
+		}
+		r.Token = entry.Token
+		if r.Token == "" {
+			r.Token = config.OwnerTokens[r.Owner]
+		}
+		repos = append(repos, r)
+	}
+	var alerts AlertsConfig
+	if config.Alerts != nil {
+		alerts = AlertsConfig(*config.Alerts)
+		alerts.Server = strings.TrimSuffix(alerts.Server, "/")
+		server, err := url.Parse(alerts.Server)
+		if err != nil || !server.IsAbs() || server.Host == "" {
+			return Config{}, fmt.Errorf("Incorrect alerts server, should be an absolute url like https://alertmanager.example.com, got `%s`", config.Alerts.Server)
+		}
+	}
+	refreshInterval, err := time.ParseDuration(config.RefreshInterval)
+	if err != nil || refreshInterval <= 0 {
+		refreshInterval = DEFAULT_REFRESH_INTERVAL
+	}
+	for i, tab := range config.CustomTabs {
+		if tab.Title == "" || tab.Query == "" {
+			return Config{}, fmt.Errorf("Incorrect tab, should have a title and a query, got %+v", tab)
+		}
+		isTaken := func(other CustomTab) bool { return other.Title == tab.Title }
+		if slices.Contains(BUILTIN_TABS, tab.Title) || slices.ContainsFunc(config.CustomTabs[:i], isTaken) {
+			return Config{}, fmt.Errorf("Incorrect tab, there is already a tab called %s", tab.Title)
+		}
+	}
+	var since time.Duration
+	if config.Since != "" {
+		since, err = time.ParseDuration(config.Since)
+		if err != nil || since <= 0 {
+			return Config{}, fmt.Errorf("Incorrect since, should be a duration like 168h, got `%s`", config.Since)
+		}
+	}
+	tabStyles := map[string]TabStyle{}
+	for title, style := range config.TabStyles {
+		isCustom := slices.ContainsFunc(config.CustomTabs, func(tab CustomTab) bool { return tab.Title == title })
+		if !slices.Contains(BUILTIN_TABS, title) && !isCustom {
+			return Config{}, fmt.Errorf("Incorrect tabStyles, there is no tab called %s", title)
+		}
+		var accent rl.Color
+		if style.Color != "" {
+			accent, err = parseColor(style.Color)
+			if err != nil {
+				return Config{}, fmt.Errorf("Incorrect color for tab %s: %s", title, err.Error())
+			}
 		}
+		tabStyles[title] = TabStyle{Icon: style.Icon, Accent: accent}
+	}
+	tabIntervals := map[string]time.Duration{}
+	for title, interval := range config.TabIntervals {
+		isCustom := slices.ContainsFunc(config.CustomTabs, func(tab CustomTab) bool { return tab.Title == title })
+		if !slices.Contains(BUILTIN_TABS, title) && !isCustom {
+			return Config{}, fmt.Errorf("Incorrect tabRefreshIntervals, there is no tab called %s", title)
+		}
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			return Config{}, fmt.Errorf("Incorrect refresh interval for tab %s, should be a duration like 2m, got `%s`", title, interval)
+		}
+		tabIntervals[title] = d
+	}
+	if config.MaxItems < 0 {
+		return Config{}, fmt.Errorf("Incorrect maxItems, should be zero or more, got %d", config.MaxItems)
+	}
+	httpTimeout, err := time.ParseDuration(config.HTTPTimeout)
+	if err != nil || httpTimeout <= 0 {
+		httpTimeout = DEFAULT_HTTP_TIMEOUT
+	}
+	// Draft PRs have always been hidden, so keep that as the default
+	hideDraftPRs := true
+	if config.HideDraftPRs != nil {
+		hideDraftPRs = *config.HideDraftPRs
+	}
+	keybindings, err := parseKeybindings(config.Keybindings)
+	if err != nil {
+		return Config{}, err
+	}
+	font := config.Font
+	if font == "" {
+		font = DEFAULT_FONT
+	}
+	fontSizes := DEFAULT_FONT_SIZES
+	if config.FontSizes.Header > 0 {
+		fontSizes.Header = config.FontSizes.Header
+	}
+	if config.FontSizes.Body > 0 {
+		fontSizes.Body = config.FontSizes.Body
+	}
+	if config.FontSizes.Help > 0 {
+		fontSizes.Help = config.FontSizes.Help
+	}
+	windowWidth := WINDOW_WIDTH
+	if config.WindowWidth > 0 {
+		windowWidth = max(config.WindowWidth, MIN_WINDOW_WIDTH)
+	}
+	windowHeight := WINDOW_HEIGHT
+	if config.WindowHeight > 0 {
+		windowHeight = max(config.WindowHeight, MIN_WINDOW_HEIGHT)
+	}
+	workflowRuns := config.WorkflowRuns
+	if workflowRuns <= 0 {
+		workflowRuns = DEFAULT_WORKFLOW_RUNS_PER_REPO
+	}
+	prState := config.PRState
+	if prState == "" {
+		prState = "open"
+	}
+	if !slices.Contains([]string{"open", "closed", "all"}, prState) {
+		return Config{}, fmt.Errorf("Incorrect prState, should be `open`, `closed` or `all`, got `%s`", config.PRState)
+	}
+	theme := config.Theme
+	if theme == "" {
+		theme = THEME_LIGHT
+	}
+	if theme != THEME_LIGHT && theme != THEME_DARK {
+		return Config{}, fmt.Errorf("Incorrect theme, should be `%s` or `%s`, got `%s`", THEME_LIGHT, THEME_DARK, config.Theme)
 	}
 	githubTokens := make(map[string]string)
 	tokens := os.Getenv("GH_TOKEN")
+	if config.GithubTokenFile != "" {
+		contents, err := os.ReadFile(config.GithubTokenFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("Could not read githubTokenFile: %s", err.Error())
+		}
+		tokens = strings.TrimSpace(string(contents))
+	}
 	if tokens != "" {
 		if strings.Contains(tokens, ":") {
 			hosts := strings.Split(tokens, ",")
 			for _, host := range hosts {
 				creds := strings.Split(host, ":")
 				if len(creds) != 2 {
-					return Config{}, fmt.Errorf("Could not parse host in GH_TOKEN, should be on the form hostname:token")
+					return Config{}, fmt.Errorf("Could not parse host in GitHub tokens, should be on the form hostname:token")
 				}
 				githubTokens[creds[0]] = creds[1]
 			}
@@ -115,19 +521,275 @@ func buildConfig(filename string) (Config, error) {
 		}
 	}
 	return Config{
-		Repos:        repos,
-		Alerts:       AlertsConfig(config.Alerts),
-		GithubTokens: githubTokens,
+		Repos:           repos,
+		Alerts:          alerts,
+		GithubTokens:    githubTokens,
+		RefreshInterval: refreshInterval,
+		HideDraftPRs:    hideDraftPRs,
+		Keybindings:     keybindings,
+		GithubBaseURL:   githubBaseURL,
+		GithubHost:      defaultHost,
+		ReviewRequested: config.ReviewRequested,
+		AssignedIssues:  config.AssignedIssues,
+		AllTab:          config.AllTab,
+		Notifications:   config.Notifications,
+		PRChecks:        config.PRChecks,
+		WindowWidth:     windowWidth,
+		WindowHeight:    windowHeight,
+		FontSizes:       fontSizes,
+		IssueLabels:     config.IssueLabels,
+		PRState:         prState,
+		DedupItems:      config.DedupItems,
+		IgnoreAuthors:   config.IgnoreAuthors,
+		Since:           since,
+		CustomTabs:      config.CustomTabs,
+		NotifyTabs:      config.NotifyTabs,
+		Font:            font,
+		LatestRunsOnly:  config.LatestRunsOnly,
+		WorkflowRuns:    workflowRuns,
+		HTTPTimeout:     httpTimeout,
+		Theme:           theme,
+		MaxItems:        config.MaxItems,
+		ConfirmQuit:     config.ConfirmQuit,
+		TabStyles:       tabStyles,
+		GraphQL:         config.GraphQL,
+		TabIntervals:    tabIntervals,
+		HideEmptyTabs:   config.HideEmptyTabs,
+		SkipArchived:    config.SkipArchived,
+		SkipForks:       config.SkipForks,
 	}, nil
 }
 
+// Parses a color written as #rrggbb
+func parseColor(s string) (rl.Color, error) {
+	var r, g, b uint8
+	if n, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b); err != nil || n != 3 || len(s) != 7 {
+		return rl.Color{}, fmt.Errorf("Should be written as #rrggbb, got `%s`", s)
+	}
+	return rl.NewColor(r, g, b, 255), nil
+}
+
+// Sets the font sizes, and moves everything that depends on them
+func applyFontSizes(sizes FontSizes) {
+	FONT_SIZE_HEADER = sizes.Header
+	FONT_SIZE_BODY = sizes.Body
+	FONT_SIZE_HELP = sizes.Help
+	FONT_SIZE_SMALL = sizes.Help * 7 / 10
+	RULER_Y = HEADER_Y + FONT_SIZE_HEADER + 5
+	BODY_Y = RULER_Y + 20
+	HELP_Y_PADDING = FONT_SIZE_HELP + 30
+}
+
+// Sets the colors used for drawing to the ones of the theme
+// The status colors are readable on both backgrounds, so they are left as is
+func applyTheme(theme string) {
+	switch theme {
+	case THEME_DARK:
+		COLOR_BACKGROUND = COLOR_DARK
+		COLOR_HEADER = COLOR_LIGHT
+		COLOR_ITEM = COLOR_LIGHT
+		COLOR_HELP = COLOR_LIGHT
+	default:
+		COLOR_BACKGROUND = COLOR_WHITE
+		COLOR_HEADER = COLOR_BLACK
+		COLOR_ITEM = COLOR_BLACK
+		COLOR_HELP = COLOR_BLACK
+	}
+}
+
+// Returns a function that lists the repos to fetch, where the repos written as
+// `owner/*` are replaced with all the repos of that organization
+// The organizations are listed again on every call, so that new repos show up
+// without a restart
+func listRepos(repos []Repo, tokens map[string]string, skipArchived, skipForks bool, httpClient *http.Client) func(ctx context.Context) ([]Repo, error) {
+	return func(ctx context.Context) ([]Repo, error) {
+		var expanded []Repo
+		for _, r := range repos {
+			if r.Name != "*" {
+				expanded = append(expanded, r)
+				continue
+			}
+			orgRepos, err := r.client(tokens, httpClient).ListReposForOrg(ctx, r.Owner)
+			if err != nil {
+				return []Repo{}, fmt.Errorf("Could not list the repos of %s: %s", r.Owner, err.Error())
+			}
+			for _, orgRepo := range orgRepos {
+				if (skipArchived && orgRepo.Archived) || (skipForks && orgRepo.Fork) {
+					continue
+				}
+				repo := r
+				repo.Name = orgRepo.Name
+				expanded = append(expanded, repo)
+			}
+		}
+		return expanded, nil
+	}
+}
+
+var DEFAULT_KEYBINDINGS = map[string][]string{
+	"left":     {"h", "a", "left"},
+	"down":     {"j", "s", "down"},
+	"up":       {"k", "w", "up"},
+	"right":    {"l", "d", "right"},
+	"open":     {"enter", "space"},
+	"copy":     {"y"},
+	"pageup":   {"pageup", "ctrl+u"},
+	"pagedown": {"pagedown", "ctrl+d"},
+	"sort":     {"t"},
+	"top":      {"g", "home"},
+	"bottom":   {"G", "end"},
+	"quit":     {"q"},
+	"openall":  {"O"},
+	"markread": {"m"},
+	"more":     {"L"},
+	"details":  {"v"},
+	"silence":  {"S"},
+	"openrepo": {"o"},
+}
+
+var KEY_NAMES = map[string]int32{
+	"up":        rl.KeyUp,
+	"down":      rl.KeyDown,
+	"left":      rl.KeyLeft,
+	"right":     rl.KeyRight,
+	"enter":     rl.KeyEnter,
+	"space":     rl.KeySpace,
+	"tab":       rl.KeyTab,
+	"backspace": rl.KeyBackspace,
+	"home":      rl.KeyHome,
+	"end":       rl.KeyEnd,
+	"pageup":    rl.KeyPageUp,
+	"pagedown":  rl.KeyPageDown,
+	",":         rl.KeyComma,
+	".":         rl.KeyPeriod,
+	";":         rl.KeySemicolon,
+	"'":         rl.KeyApostrophe,
+	"-":         rl.KeyMinus,
+	"=":         rl.KeyEqual,
+	"[":         rl.KeyLeftBracket,
+	"]":         rl.KeyRightBracket,
+}
+
+type Keybinding struct {
+	Key   int32
+	Shift bool
+	Ctrl  bool
+}
+
+// Returns the keybinding for the key that was pressed, with the modifiers
+// that are held down
+func pressedKeybinding(key int32) Keybinding {
+	return Keybinding{
+		Key:   key,
+		Shift: rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift),
+		Ctrl:  rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl),
+	}
+}
+
+// Formats a keybinding the same way as it is written in the config
+func (k Keybinding) String() string {
+	name := "?"
+	if k.Key >= rl.KeyA && k.Key <= rl.KeyZ {
+		name = string(rune('a' + k.Key - rl.KeyA))
+		if k.Shift {
+			name = strings.ToUpper(name)
+		}
+	} else {
+		for n, key := range KEY_NAMES {
+			if key == k.Key {
+				name = n
+			}
+		}
+		if k.Shift {
+			name = "shift+" + name
+		}
+	}
+	if k.Ctrl {
+		name = "ctrl+" + name
+	}
+	return name
+}
+
+// Parses a key name such as "k", "G", "up", "enter" or "ctrl+d" to a keybinding
+// Uppercase letters are the same as the lowercase letter with shift
+func parseKey(name string) (Keybinding, bool) {
+	var binding Keybinding
+	for {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "ctrl+") && len(name) > len("ctrl+") {
+			binding.Ctrl = true
+			name = name[len("ctrl+"):]
+		} else if strings.HasPrefix(lower, "shift+") && len(name) > len("shift+") {
+			binding.Shift = true
+			name = name[len("shift+"):]
+		} else {
+			break
+		}
+	}
+	if len(name) == 1 && name[0] >= 'A' && name[0] <= 'Z' {
+		binding.Shift = true
+	}
+	name = strings.ToLower(name)
+	if key, ok := KEY_NAMES[name]; ok {
+		binding.Key = key
+		return binding, true
+	}
+	if len(name) == 1 && name[0] >= 'a' && name[0] <= 'z' {
+		binding.Key = rl.KeyA + int32(name[0]-'a')
+		return binding, true
+	}
+	return Keybinding{}, false
+}
+
+// Returns the keybindings for each action, using the default keys for actions
+// that are not configured
+func parseKeybindings(configured map[string][]string) (map[string][]Keybinding, error) {
+	keybindings := make(map[string][]Keybinding)
+	for action, defaultKeys := range DEFAULT_KEYBINDINGS {
+		names, ok := configured[action]
+		if !ok {
+			names = defaultKeys
+		}
+		for _, name := range names {
+			key, ok := parseKey(name)
+			if !ok {
+				return nil, fmt.Errorf("Unknown key `%s` for action `%s`", name, action)
+			}
+			keybindings[action] = append(keybindings[action], key)
+		}
+	}
+	for action := range configured {
+		if _, ok := DEFAULT_KEYBINDINGS[action]; !ok {
+			return nil, fmt.Errorf("Unknown action `%s` in keybindings", action)
+		}
+	}
+	return keybindings, nil
+}
+
 type State struct {
 	TabIDs             []string
 	SelectedTab        string
 	TabDisplays        map[string]TabDisplay
 	TabData            map[string]TabData
 	ShouldClose        bool
+	Filtering          bool
 	NotificationSentAt map[string]time.Time
+	NotifiedItems      map[string][]Item
+	// Waiting for confirmation to open many items at once
+	ConfirmOpenAll bool
+	// Waiting for the quit key to be pressed again
+	ConfirmQuit bool
+	// Typing how long to silence the selected alert for
+	Silencing    bool
+	SilenceInput string
+	// A count typed before a motion, like the 5 in 5j, or 0 if there is none
+	PendingCount   int
+	PendingCountAt time.Time
+	// Held by the updaters while they write TabData and by the render loop
+	// during each frame
+	mu *sync.Mutex
+	// Leave the tabs without items out of the header
+	HideEmptyTabs bool
 }
 
 func newState() State {
@@ -138,12 +800,59 @@ func newState() State {
 		TabData:            map[string]TabData{},
 		ShouldClose:        false,
 		NotificationSentAt: map[string]time.Time{},
+		NotifiedItems:      map[string][]Item{},
+		mu:                 &sync.Mutex{},
+	}
+}
+
+// Returns the tabs that are shown in the header, which are all tabs unless
+// HideEmptyTabs is set
+// Tabs that are loading or failed are shown even if they are empty, and the
+// first tab is shown if all tabs are empty, so that something can be selected
+func (s State) visibleTabs() []string {
+	if !s.HideEmptyTabs {
+		return s.TabIDs
+	}
+	var tabs []string
+	for _, tabID := range s.TabIDs {
+		data := s.TabData[tabID]
+		if len(data.Items) > 0 || data.Loading || data.Err != nil {
+			tabs = append(tabs, tabID)
+		}
+	}
+	if len(tabs) == 0 && len(s.TabIDs) > 0 {
+		tabs = s.TabIDs[:1]
+	}
+	return tabs
+}
+
+// Select the closest tab after the selected one, or before it if there is none,
+// when the selected tab is hidden
+func keepSelectedTabVisible(state *State) {
+	tabs := state.visibleTabs()
+	if len(tabs) == 0 || slices.Contains(tabs, state.SelectedTab) {
+		return
 	}
+	selected := slices.Index(state.TabIDs, state.SelectedTab)
+	state.SelectedTab = tabs[len(tabs)-1]
+	for _, tabID := range tabs {
+		if slices.Index(state.TabIDs, tabID) > selected {
+			state.SelectedTab = tabID
+			break
+		}
+	}
+}
+
+func (s *State) markViewed(tabID string) {
+	tab := s.TabDisplays[tabID]
+	tab.LastViewedAt = time.Now()
+	tab.ViewedItems = s.TabData[tabID].Items
+	s.TabDisplays[tabID] = tab
 }
 
-func (s *State) addTab(title string, itemsGetter func() ([]Item, error)) {
+func (s *State) addTab(title string, itemsGetter func(ctx context.Context) ([]Item, error)) {
 	s.TabIDs = append(s.TabIDs, title)
-	s.TabData[title] = TabData{GetItems: itemsGetter}
+	s.TabData[title] = TabData{GetItems: itemsGetter, Loading: true, LoadMore: make(chan struct{}, 1)}
 	s.TabDisplays[title] = TabDisplay{Title: title}
 	if s.SelectedTab == "" {
 		s.SelectedTab = title
@@ -153,234 +862,1782 @@ func (s *State) addTab(title string, itemsGetter func() ([]Item, error)) {
 type TabDisplay struct {
 	Title        string
 	SelectedItem int
+	ScrollOffset int
 	LastViewedAt time.Time
+	// The items when the tab was last viewed, to count the new ones
+	ViewedItems []Item
+	Filter      string
+	SortMode    SortMode
+	DisplayMode DisplayMode
+	// The url of the selected item in the last frame, to find it again when
+	// the items change
+	SelectedURL string
+	Style       TabStyle
 }
 
 type TabData struct {
 	Items      []Item
 	ModifiedAt time.Time
-	GetItems   func() ([]Item, error)
+	GetItems   func(ctx context.Context) ([]Item, error)
+	Err        error
+	FailedAt   time.Time
+	// True until the first fetch has succeeded
+	Loading bool
+	// When the last fetch succeeded, which is not the same as when the items changed
+	FetchedAt time.Time
+	// Decides if the new items are worth a notification, always notifies if nil
+	ShouldNotify func(added []Item) bool
+	// True while the items are being fetched
+	Fetching bool
+	// How many items each request gets, or no limit if zero
+	MaxItems int
+	// Silences an alert with the labels for d, nil for tabs without alerts
+	Silence func(ctx context.Context, labels map[string]string, d time.Duration) error
+	// How often the tab is fetched
+	RefreshInterval time.Duration
+	// Makes the updater get more items for the tab
+	LoadMore chan struct{}
+}
+
+// Returns the items of a tab that match the tab's filter, in the tab's sort order
+func (s State) visibleItems(tabID string) []Item {
+	var items []Item
+	filter := strings.ToLower(s.TabDisplays[tabID].Filter)
+	for _, item := range s.TabData[tabID].Items {
+		if strings.Contains(strings.ToLower(item.Label()), filter) {
+			items = append(items, item)
+		}
+	}
+	switch s.TabDisplays[tabID].SortMode {
+	case SORT_DEFAULT:
+	case SORT_NEWEST:
+		slices.SortStableFunc(items, func(a, b Item) int {
+			return -1 * a.CreatedAt.Compare(b.CreatedAt)
+		})
+	case SORT_OLDEST:
+		slices.SortStableFunc(items, func(a, b Item) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+	case SORT_ALPHABETICAL:
+		slices.SortStableFunc(items, func(a, b Item) int {
+			return strings.Compare(strings.ToLower(a.Label()), strings.ToLower(b.Label()))
+		})
+	}
+	return items
+}
+
+type SortMode int
+
+const (
+	// The order the getter returned the items in
+	SORT_DEFAULT SortMode = iota
+	SORT_NEWEST
+	SORT_OLDEST
+	SORT_ALPHABETICAL
+)
+
+func (m SortMode) String() string {
+	switch m {
+	case SORT_NEWEST:
+		return "newest"
+	case SORT_OLDEST:
+		return "oldest"
+	case SORT_ALPHABETICAL:
+		return "a-z"
+	default:
+		return "default"
+	}
+}
+
+type DisplayMode int
+
+const (
+	// One line per item
+	DISPLAY_COMPACT DisplayMode = iota
+	// The time and url of each item are shown below its value
+	DISPLAY_DETAILED
+)
+
+func (m DisplayMode) String() string {
+	switch m {
+	case DISPLAY_DETAILED:
+		return "detailed"
+	default:
+		return "compact"
+	}
 }
 
 type Item struct {
 	Value       string
 	URL         string
 	Application string
+	// Drawn with COLOR_ITEM if not set
+	Color rl.Color
+	// Shown as an age next to the value, unless it is zero
+	CreatedAt time.Time
+	// Where the item comes from, like the repo, drawn in a column before the value
+	Source string
+	// The labels of an alert, nil for other items
+	Labels map[string]string
+	// The page of the item's repo that lists items like it, like the repo's
+	// pull requests for a PR, empty if it has no repo
+	RepoPage string
+	// The conclusion of a workflow run, or its status if it has not finished,
+	// empty for other items
+	Status string
+}
+
+// Returns the value with the source in front, for where there is no column
+func (i Item) Label() string {
+	if i.Source == "" {
+		return i.Value
+	}
+	return fmt.Sprintf("%s %s", i.Source, i.Value)
 }
 
 func main() {
-	config, err := buildConfig("config.json")
+	configFile := flag.String("config", "", "Path to the config file, defaults to $DAESHBOARD_CONFIG, $XDG_CONFIG_HOME/daeshboard/config.json or ./config.json, or config.yaml in the same places")
+	jsonOutput := flag.Bool("json", false, "Fetch all tabs once, print them as json and exit, without opening a window")
+	flag.Parse()
+	if *configFile == "" {
+		*configFile = os.Getenv("DAESHBOARD_CONFIG")
+	}
+	if *configFile == "" {
+		*configFile = defaultConfigFile()
+	}
+	config, err := buildConfig(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not parse config file: %s\n", err.Error())
 		os.Exit(1)
 	}
-	state := newState()
-	state.addTab("PRs", getPrs(config.Repos, config.GithubTokens))
-	state.addTab("Issues", getIssues(config.Repos, config.GithubTokens))
-	state.addTab("Alerts", getAlerts(config.Alerts))
-	state.addTab("Workflows", getWorkflowRuns(config.Repos, config.GithubTokens))
-	go updateData(&state)
+	if *jsonOutput {
+		if err := printJSON(buildState(config)); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	stateFile := stateFilePath(*configFile)
+	applyTheme(config.Theme)
+	state := buildState(config)
+	if err := restoreState(stateFile, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not restore state: %s\n", err.Error())
+	}
+	// Stopped on quit or when the config is reloaded
+	stopUpdating := startUpdating(state, config.MaxItems)
+	configChanges := make(chan Config)
+	go watchConfig(*configFile, configChanges)
 
 	if os.Getenv("LOG") == "false" {
 		rl.SetTraceLogLevel(rl.LogNone)
 	}
 	rl.SetTargetFPS(60)
-	rl.SetConfigFlags(rl.FlagWindowResizable)
+	// With the high dpi flag, raylib scales everything that is drawn by the
+	// monitor's scale, so the layout stays in unscaled pixels and only the
+	// fonts need to be loaded larger to stay sharp
+	rl.SetConfigFlags(rl.FlagWindowResizable | rl.FlagWindowHighdpi)
 	windowTitle := PROGRAM_NAME
-	rl.InitWindow(int32(WINDOW_WIDTH), int32(WINDOW_HEIGHT), windowTitle)
-	headerFont := rl.LoadFontEx("JetBrainsMonoNerdFont-Medium.ttf", 2*int32(FONT_SIZE_HEADER), nil, 256)
-	bodyFont := rl.LoadFontEx("JetBrainsMonoNerdFont-Medium.ttf", 2*int32(FONT_SIZE_BODY), nil, 256)
-	helpFont := rl.LoadFontEx("JetBrainsMonoNerdFont-Medium.ttf", 2*int32(FONT_SIZE_HELP), nil, 256)
+	// Open the window where it was when the program exited, if it was saved
+	window, err := readWindow(stateFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not restore the window: %s\n", err.Error())
+	}
+	if window.Width == 0 {
+		rl.InitWindow(int32(config.WindowWidth), int32(config.WindowHeight), windowTitle)
+	} else {
+		rl.InitWindow(int32(max(window.Width, MIN_WINDOW_WIDTH)), int32(max(window.Height, MIN_WINDOW_HEIGHT)), windowTitle)
+		placeWindow(window)
+	}
+	rl.SetWindowMinSize(MIN_WINDOW_WIDTH, MIN_WINDOW_HEIGHT)
+	// Escape is used to clear the filter, so don't close the window on it
+	rl.SetExitKey(rl.KeyNull)
+	applyFontSizes(config.FontSizes)
+	headerFont := loadFont(config.Font, FONT_SIZE_HEADER, config.TabStyles)
+	bodyFont := loadFont(config.Font, FONT_SIZE_BODY, config.TabStyles)
+	helpFont := loadFont(config.Font, FONT_SIZE_HELP, config.TabStyles)
 	defer rl.CloseWindow()
 
 	for !rl.WindowShouldClose() && !state.ShouldClose {
+		select {
+		case newConfig := <-configChanges:
+			// Start over with fresh tabs, once the old updaters have stopped
+			// touching the old state
+			fmt.Println("Reloaded config")
+			stopUpdating()
+			if err := saveState(stateFile, *state); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not save state: %s\n", err.Error())
+			}
+			// The icons are loaded with the fonts
+			if newConfig.Font != config.Font || newConfig.FontSizes != config.FontSizes || !maps.Equal(newConfig.TabStyles, config.TabStyles) {
+				applyFontSizes(newConfig.FontSizes)
+				unloadFonts(headerFont, bodyFont, helpFont)
+				headerFont = loadFont(newConfig.Font, FONT_SIZE_HEADER, newConfig.TabStyles)
+				bodyFont = loadFont(newConfig.Font, FONT_SIZE_BODY, newConfig.TabStyles)
+				helpFont = loadFont(newConfig.Font, FONT_SIZE_HELP, newConfig.TabStyles)
+			}
+			config = newConfig
+			applyTheme(config.Theme)
+			state = buildState(config)
+			if err := restoreState(stateFile, state); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not restore state: %s\n", err.Error())
+			}
+			stopUpdating = startUpdating(state, config.MaxItems)
+		default:
+		}
+
 		rl.BeginDrawing()
-		rl.ClearBackground(rl.RayWhite)
+		rl.ClearBackground(COLOR_BACKGROUND)
 
-		reactToInput(&state)
+		state.mu.Lock()
+		keepSelectedTabVisible(state)
+		followSelection(state)
+		reactToInput(state, config.Keybindings, config.ConfirmQuit)
+		reactToMouse(state)
+		rememberSelection(state)
+		scrollToSelection(state)
 
-		drawWindowTitle(&state)
-		drawHeaders(state, headerFont, float32(FONT_SIZE_HEADER))
+		drawWindowTitle(state)
+		drawHeaders(*state, headerFont, float32(FONT_SIZE_HEADER))
 		drawRuler()
-		drawBody(state, bodyFont, float32(FONT_SIZE_BODY))
-		drawHelp(state, helpFont, float32(FONT_SIZE_HELP))
+		drawLastUpdated(*state, helpFont, float32(FONT_SIZE_SMALL))
+		drawBody(*state, bodyFont, float32(FONT_SIZE_BODY))
+		drawHelp(*state, config.Keybindings, helpFont, float32(FONT_SIZE_HELP))
+		drawRateLimit(helpFont, float32(FONT_SIZE_SMALL))
+		drawSpinner(*state, headerFont, float32(FONT_SIZE_HEADER))
 
-		notifyIfNeeded(&state)
+		notifyIfNeeded(state, config.NotifyTabs)
+		state.mu.Unlock()
 
 		rl.EndDrawing()
 	}
+	stopUpdating()
+	if err := saveState(stateFile, *state); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save state: %s\n", err.Error())
+	}
 }
 
-func updateData(state *State) {
-	for _, tabID := range state.TabIDs {
-		items, err := state.TabData[tabID].GetItems()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to get items for tab %s: %s\n", tabID, err.Error())
-			os.Exit(1)
-		}
-		if state.TabData[tabID].ModifiedAt.IsZero() || !slices.Equal(items, state.TabData[tabID].Items) {
-			fmt.Printf("Updated items for tab %s\n", tabID)
-			state.TabData[tabID] = TabData{
-				Items:      items,
-				ModifiedAt: time.Now(),
+// The names of the config file that are looked for, in order
+var CONFIG_FILE_NAMES = []string{"config.json", "config.yaml", "config.yml"}
+
+// Returns the config file in the user's config directory if there is one, and
+// the config file in the working directory otherwise, which is config.json if
+// there is none
+func defaultConfigFile() string {
+	var dirs []string
+	if configDir, err := xdgDir("XDG_CONFIG_HOME", ".config"); err == nil {
+		dirs = append(dirs, filepath.Join(configDir, "daeshboard"))
+	}
+	for _, dir := range append(dirs, ".") {
+		for _, name := range CONFIG_FILE_NAMES {
+			filename := filepath.Join(dir, name)
+			if _, err := os.Stat(filename); err == nil {
+				return filename
 			}
 		}
 	}
-	time.Sleep(10 * time.Second)
+	return "config.json"
 }
 
-func getPrs(repos []Repo, tokens map[string]string) func() ([]Item, error) {
-	return func() ([]Item, error) {
-		var items []Item
-		for _, r := range repos {
-			prs, err := github.ListPRsForRepo(r.Host, r.Owner, r.Name, tokens[r.Host])
-			if err != nil {
-				return []Item{}, fmt.Errorf("Failed to list PRs: %s", err.Error())
-			}
-			for _, pr := range prs {
-				items = append(items, Item{
-					Value: fmt.Sprintf("%s: %s", r, pr.Title),
-					URL:   pr.HtmlURL,
-				})
-			}
-		}
-		return items, nil
+// Returns the directory in the environment variable, or the directory at
+// fallback in the home directory if it is not set, like ~/.config for
+// $XDG_CONFIG_HOME
+// This is the same on all platforms, unlike os.UserConfigDir
+func xdgDir(envVar, fallback string) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir, nil
 	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback), nil
 }
 
-func getIssues(repos []Repo, tokens map[string]string) func() ([]Item, error) {
-	return func() ([]Item, error) {
-		var items []Item
-		for _, r := range repos {
-			issues, err := github.ListIssuesForRepo(r.Host, r.Owner, r.Name, tokens[r.Host])
-			if err != nil {
-				return []Item{}, fmt.Errorf("Failed to list issues: %s", err.Error())
-			}
-			for _, issue := range issues {
-				items = append(items, Item{
-					Value: fmt.Sprintf("%s: %s", r, issue.Title),
-					URL:   issue.HtmlURL,
-				})
+// Returns where to keep the state, in $XDG_DATA_HOME/daeshboard or
+// ~/.local/share/daeshboard
+// A state file next to the config file from before is moved there
+func stateFilePath(configFile string) string {
+	dataDir, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return filepath.Join(filepath.Dir(configFile), STATE_FILE)
+	}
+	filename := filepath.Join(dataDir, "daeshboard", STATE_FILE)
+	oldFilename := filepath.Join(filepath.Dir(configFile), STATE_FILE)
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(oldFilename); err == nil {
+			if err := os.MkdirAll(filepath.Dir(filename), 0o755); err == nil {
+				if err := os.Rename(oldFilename, filename); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not move the state file to %s: %s\n", filename, err.Error())
+					return oldFilename
+				}
 			}
 		}
-		return items, nil
 	}
+	return filename
 }
 
-type Alert struct {
-	Annotations struct {
-		Description string `json:"description"`
-	} `json:"annotations"`
-	StartsAt time.Time `json:"startsAt"`
+// The parts of a tab's state that are kept between restarts, so that tabs
+// that were viewed before a restart are not marked as updated after it, and
+// the same tab and item are selected
+type savedTab struct {
+	Items              []Item    `json:"items"`
+	ModifiedAt         time.Time `json:"modifiedAt"`
+	LastViewedAt       time.Time `json:"lastViewedAt"`
+	ViewedItems        []Item    `json:"viewedItems"`
+	NotificationSentAt time.Time `json:"notificationSentAt"`
+	NotifiedItems      []Item    `json:"notifiedItems"`
+	Selected           bool      `json:"selected"`
+	SelectedItem       int       `json:"selectedItem"`
 }
 
-func getAlerts(alertsConfig AlertsConfig) func() ([]Item, error) {
-	return func() ([]Item, error) {
-		var alerts []Alert
-		query := fmt.Sprintf("receiver=%s&silenced=false&inhibited=false", url.QueryEscape(alertsConfig.Receiver))
-		url := fmt.Sprintf("%s/api/v2/alerts?%s", alertsConfig.Server, query)
-		resp, err := http.Get(url)
-		if err != nil {
-			return []Item{}, fmt.Errorf("Could not get alerts: %s\n", err.Error())
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 {
-			return []Item{}, fmt.Errorf("Got non-200 status code when getting alerts: %s\n", resp.Status)
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
-			return []Item{}, fmt.Errorf("Could not parse alerts response: %s", err.Error())
-		}
-		slices.SortFunc(alerts, func(a, b Alert) int {
-			return -1 * a.StartsAt.Compare(b.StartsAt)
-		})
-		var items []Item
-		for _, a := range alerts {
-			items = append(items, Item{
-				Value: a.Annotations.Description,
-				URL:   fmt.Sprintf("%s/#/alerts?%s", alertsConfig.Server, query),
-			})
+type savedWindow struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type savedState struct {
+	Tabs   map[string]savedTab `json:"tabs"`
+	Window savedWindow         `json:"window"`
+}
+
+// Returns the saved state, which is empty if there is none yet
+func readState(filename string) (savedState, error) {
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return savedState{}, nil
+	} else if err != nil {
+		return savedState{}, fmt.Errorf("Could not read state file: %s", err.Error())
+	}
+	var saved savedState
+	if err := json.Unmarshal(contents, &saved); err != nil {
+		return savedState{}, fmt.Errorf("Could not parse state file: %s", err.Error())
+	}
+	if saved.Tabs == nil {
+		// Older state files have the tabs at the top level
+		if err := json.Unmarshal(contents, &saved.Tabs); err != nil {
+			return savedState{}, fmt.Errorf("Could not parse state file: %s", err.Error())
 		}
-		return items, nil
 	}
+	return saved, nil
 }
 
-func getWorkflowRuns(repos []Repo, tokens map[string]string) func() ([]Item, error) {
-	return func() ([]Item, error) {
-		var items []Item
-		for _, r := range repos {
-			runs, err := github.ListWorkflowRunsForRepo(r.Host, r.Owner, r.Name, tokens[r.Host])
-			if err != nil {
+func readWindow(filename string) (savedWindow, error) {
+	saved, err := readState(filename)
+	return saved.Window, err
+}
+
+// Move and resize the window to the saved one, kept within the current monitor
+// in case the saved position is on a monitor that is gone
+func placeWindow(window savedWindow) {
+	monitor := rl.GetCurrentMonitor()
+	position := rl.GetMonitorPosition(monitor)
+	monitorX, monitorY := int(position.X), int(position.Y)
+	width := min(window.Width, rl.GetMonitorWidth(monitor))
+	height := min(window.Height, rl.GetMonitorHeight(monitor))
+	x := max(monitorX, min(window.X, monitorX+rl.GetMonitorWidth(monitor)-width))
+	y := max(monitorY, min(window.Y, monitorY+rl.GetMonitorHeight(monitor)-height))
+	rl.SetWindowSize(width, height)
+	rl.SetWindowPosition(x, y)
+}
+
+func saveState(filename string, state State) error {
+	tabs := make(map[string]savedTab)
+	for _, tabID := range state.TabIDs {
+		tabs[tabID] = savedTab{
+			Items:              state.TabData[tabID].Items,
+			ModifiedAt:         state.TabData[tabID].ModifiedAt,
+			LastViewedAt:       state.TabDisplays[tabID].LastViewedAt,
+			ViewedItems:        state.TabDisplays[tabID].ViewedItems,
+			NotificationSentAt: state.NotificationSentAt[tabID],
+			NotifiedItems:      state.NotifiedItems[tabID],
+			Selected:           tabID == state.SelectedTab,
+			SelectedItem:       state.TabDisplays[tabID].SelectedItem,
+		}
+	}
+	position := rl.GetWindowPosition()
+	window := savedWindow{
+		X:      int(position.X),
+		Y:      int(position.Y),
+		Width:  rl.GetScreenWidth(),
+		Height: rl.GetScreenHeight(),
+	}
+	contents, err := json.MarshalIndent(savedState{Tabs: tabs, Window: window}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Could not encode state: %s", err.Error())
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return fmt.Errorf("Could not create the directory for the state file: %s", err.Error())
+	}
+	if err := os.WriteFile(filename, contents, 0o644); err != nil {
+		return fmt.Errorf("Could not write state file: %s", err.Error())
+	}
+	return nil
+}
+
+// Restore the saved state for the tabs that exist in state
+// It's not an error if there is no saved state yet
+func restoreState(filename string, state *State) error {
+	stored, err := readState(filename)
+	if err != nil {
+		return err
+	}
+	for _, tabID := range state.TabIDs {
+		saved, ok := stored.Tabs[tabID]
+		if !ok {
+			continue
+		}
+		data := state.TabData[tabID]
+		data.Items = saved.Items
+		data.ModifiedAt = saved.ModifiedAt
+		state.TabData[tabID] = data
+		display := state.TabDisplays[tabID]
+		display.LastViewedAt = saved.LastViewedAt
+		display.ViewedItems = saved.ViewedItems
+		// The saved items are shown until the first fetch is done
+		display.SelectedItem = max(0, min(saved.SelectedItem, len(saved.Items)-1))
+		state.TabDisplays[tabID] = display
+		if saved.Selected {
+			state.SelectedTab = tabID
+		}
+		state.NotificationSentAt[tabID] = saved.NotificationSentAt
+		state.NotifiedItems[tabID] = saved.NotifiedItems
+	}
+	return nil
+}
+
+type jsonItem struct {
+	Value     string    `json:"value"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	Source    string    `json:"source,omitempty"`
+}
+
+type jsonTab struct {
+	Tab   string     `json:"tab"`
+	Items []jsonItem `json:"items"`
+	Error string     `json:"error,omitempty"`
+}
+
+// Fetch all tabs once and print them to stdout, for use without a window
+// Returns an error after printing if any of the tabs failed
+func printJSON(state *State) error {
+	var tabs []jsonTab
+	failed := 0
+	for _, tabID := range state.TabIDs {
+		tab := jsonTab{Tab: tabID, Items: []jsonItem{}}
+		items, err := state.TabData[tabID].GetItems(github.WithMaxItems(context.Background(), state.TabData[tabID].MaxItems))
+		if err != nil {
+			tab.Error = err.Error()
+			failed++
+		}
+		for _, item := range items {
+			tab.Items = append(tab.Items, jsonItem{Value: item.Value, URL: item.URL, CreatedAt: item.CreatedAt, Source: item.Source})
+		}
+		tabs = append(tabs, tab)
+	}
+	contents, err := json.MarshalIndent(tabs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Could not encode tabs: %s", err.Error())
+	}
+	fmt.Println(string(contents))
+	if failed > 0 {
+		return fmt.Errorf("Failed to fetch %d of %d tabs", failed, len(tabs))
+	}
+	return nil
+}
+
+// Unload fonts, except raylib's default font which it unloads by itself
+func unloadFonts(fonts ...rl.Font) {
+	for _, font := range fonts {
+		if font.Texture.ID != rl.GetFontDefault().Texture.ID {
+			rl.UnloadFont(font)
+		}
+	}
+}
+
+// Returns the first 256 codepoints and the symbols used for PR checks and the
+// spinner
+func fontCodepoints(tabStyles map[string]TabStyle) []rune {
+	var codepoints []rune
+	for r := rune(0); r < 256; r++ {
+		codepoints = append(codepoints, r)
+	}
+	codepoints = append(codepoints, []rune(SPINNER_FRAMES)...)
+	for _, style := range tabStyles {
+		for _, r := range style.Icon {
+			if !slices.Contains(codepoints, r) {
+				codepoints = append(codepoints, r)
+			}
+		}
+	}
+	return append(codepoints, '✓', '✗', '●')
+}
+
+// Load a font, falling back to raylib's default font if the file is missing
+// Must be called after the window is created, to know the monitor's scale
+func loadFont(filename string, fontSize int, tabStyles map[string]TabStyle) rl.Font {
+	if _, err := os.Stat(filename); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load font, using the default font instead: %s\n", err.Error())
+		return rl.GetFontDefault()
+	}
+	scale := max(1, rl.GetWindowScaleDPI().X)
+	return rl.LoadFontEx(filename, int32(2*float32(fontSize)*scale), fontCodepoints(tabStyles))
+}
+
+// Returns a client that goes through the proxy in $HTTPS_PROXY, $HTTP_PROXY
+// and $NO_PROXY, if there is one, which may also be a socks5:// proxy
+func newHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func buildState(config Config) *State {
+	httpClient := newHTTPClient(config.HTTPTimeout)
+	// The api of githubBaseURL, for the tabs that are not per repo
+	githubClient := github.Client{BaseURL: config.GithubBaseURL, Token: config.GithubTokens[config.GithubHost], HTTPClient: httpClient}
+	repos := listRepos(config.Repos, config.GithubTokens, config.SkipArchived, config.SkipForks, httpClient)
+	state := newState()
+	// Shared by their own tabs and the All tab
+	prs := sharedGetter(createdWithin(config.Since, getPrs(repos, config.GithubTokens, config.HideDraftPRs, config.PRState, config.PRChecks, config.IgnoreAuthors, config.GraphQL, httpClient)))
+	issues := sharedGetter(getIssues(repos, config.GithubTokens, config.IssueLabels, config.Since, config.GraphQL, httpClient))
+	alerts := sharedGetter(getAlerts(config.Alerts, httpClient))
+	sources := []itemSource{{Name: "PR", GetItems: prs}, {Name: "Issue", GetItems: issues}}
+	if config.Alerts.Server != "" {
+		sources = append(sources, itemSource{Name: "Alert", GetItems: alerts})
+	}
+	if config.AllTab {
+		state.addTab("All", getAll(sources))
+	}
+	state.addTab("PRs", prs)
+	if config.ReviewRequested {
+		state.addTab("Reviews", getReviewRequestedPRs(githubClient))
+	}
+	state.addTab("Issues", issues)
+	if config.AssignedIssues {
+		state.addTab("Assigned", getAssignedIssues(repos, config.GithubTokens, httpClient))
+	}
+	if config.Notifications {
+		state.addTab("Inbox", getNotifications(githubClient))
+	}
+	if config.Alerts.Server != "" {
+		state.addTab("Alerts", alerts)
+		alerts := state.TabData["Alerts"]
+		alerts.Silence = silenceAlert(config.Alerts, httpClient)
+		state.TabData["Alerts"] = alerts
+	}
+	state.addTab("Workflows", createdWithin(config.Since, getWorkflowRuns(repos, config.GithubTokens, config.LatestRunsOnly, config.WorkflowRuns, httpClient)))
+	workflows := state.TabData["Workflows"]
+	workflows.ShouldNotify = hasFailedRun
+	state.TabData["Workflows"] = workflows
+	for _, tab := range config.CustomTabs {
+		state.addTab(tab.Title, getSearchResults(githubClient, tab.Query))
+	}
+	state.HideEmptyTabs = config.HideEmptyTabs
+	for _, tabID := range state.TabIDs {
+		data := state.TabData[tabID]
+		data.MaxItems = config.MaxItems
+		data.RefreshInterval = config.RefreshInterval
+		if interval, ok := config.TabIntervals[tabID]; ok {
+			data.RefreshInterval = interval
+		}
+		state.TabData[tabID] = data
+		display := state.TabDisplays[tabID]
+		display.Style = config.TabStyles[tabID]
+		state.TabDisplays[tabID] = display
+	}
+	if config.DedupItems {
+		for _, tabID := range state.TabIDs {
+			data := state.TabData[tabID]
+			data.GetItems = withoutDuplicates(tabID, data.GetItems)
+			state.TabData[tabID] = data
+		}
+	}
+	return &state
+}
+
+// Wraps a getter to drop items that were created longer ago than since, unless
+// since is zero
+func createdWithin(since time.Duration, getItems func(ctx context.Context) ([]Item, error)) func(ctx context.Context) ([]Item, error) {
+	if since == 0 {
+		return getItems
+	}
+	return func(ctx context.Context) ([]Item, error) {
+		items, err := getItems(ctx)
+		if err != nil {
+			return items, err
+		}
+		var recent []Item
+		for _, item := range items {
+			if time.Since(item.CreatedAt) <= since {
+				recent = append(recent, item)
+			}
+		}
+		return recent, nil
+	}
+}
+
+// Wraps a getter to drop items with the same value and url as an earlier item
+// The url alone is not enough, since all alerts link to the same page
+func withoutDuplicates(tabID string, getItems func(ctx context.Context) ([]Item, error)) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		items, err := getItems(ctx)
+		if err != nil {
+			return items, err
+		}
+		type key struct{ value, url string }
+		seen := make(map[key]bool)
+		var unique []Item
+		for _, item := range items {
+			k := key{item.Label(), item.URL}
+			if !seen[k] {
+				seen[k] = true
+				unique = append(unique, item)
+			}
+		}
+		if removed := len(items) - len(unique); removed > 0 {
+			fmt.Printf("Removed %d duplicate items from tab %s\n", removed, tabID)
+		}
+		return unique, nil
+	}
+}
+
+// Check the config file for changes every few seconds and send the new config
+// on changes when it is valid
+// An invalid config is logged, so that the old config keeps running
+func watchConfig(filename string, changes chan<- Config) {
+	var lastModified time.Time
+	if info, err := os.Stat(filename); err == nil {
+		lastModified = info.ModTime()
+	}
+	for {
+		time.Sleep(CONFIG_POLL_INTERVAL)
+		info, err := os.Stat(filename)
+		if err != nil || info.ModTime().Equal(lastModified) {
+			continue
+		}
+		lastModified = info.ModTime()
+		config, err := buildConfig(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not reload config file, keeping the old config: %s\n", err.Error())
+			continue
+		}
+		changes <- config
+	}
+}
+
+// Start fetching the items of each tab in its own goroutine, so that every tab
+// has its own refresh interval and a slow tab does not delay the others
+// Returns a function that cancels the pending requests and waits for the
+// updaters to return, after which the state is only used by the caller
+func startUpdating(state *State, maxItems int) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, tabID := range state.TabIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			updateTab(ctx, state, tabID, maxItems)
+		}()
+	}
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// Fetch the items for a tab every refresh interval of the tab, until ctx is
+// cancelled
+// Loading more items for the tab raises its limit by maxItems and fetches again
+func updateTab(ctx context.Context, state *State, tabID string, maxItems int) {
+	for {
+		state.mu.Lock()
+		data := state.TabData[tabID]
+		data.Fetching = true
+		state.TabData[tabID] = data
+		state.mu.Unlock()
+		items, err := data.GetItems(github.WithMaxItems(ctx, data.MaxItems))
+		if ctx.Err() != nil {
+			return
+		}
+		state.mu.Lock()
+		data = state.TabData[tabID]
+		data.Fetching = false
+		if err != nil {
+			// Keep the previous items around, they are just stale
+			data.Err = err
+			data.FailedAt = time.Now()
+		} else {
+			// Only a different set of items marks the tab as modified, the
+			// items themselves are always replaced so that their values stay
+			// fresh
+			if data.ModifiedAt.IsZero() || itemsSignature(items) != itemsSignature(data.Items) {
+				fmt.Printf("Updated items for tab %s\n", tabID)
+				data.ModifiedAt = time.Now()
+			}
+			data.Items = items
+			data.Err = nil
+			data.Loading = false
+			data.FetchedAt = time.Now()
+		}
+		state.TabData[tabID] = data
+		state.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-data.LoadMore:
+			state.mu.Lock()
+			if data := state.TabData[tabID]; data.MaxItems > 0 {
+				data.MaxItems += maxItems
+				state.TabData[tabID] = data
+			}
+			state.mu.Unlock()
+		case <-time.After(data.RefreshInterval):
+		}
+	}
+}
+
+// Identifies an item across fetches, even if its value changed
+// The creation time is included since alerts all share the same URL
+func itemKey(item Item) string {
+	return fmt.Sprintf("%s %d", item.URL, item.CreatedAt.UnixNano())
+}
+
+// Returns a hash of the set of items, which does not depend on their order
+func itemsSignature(items []Item) uint64 {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = itemKey(item)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	h := fnv.New64a()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// Calls fetch for all repos concurrently, at most MAX_CONCURRENT_REQUESTS at a time
+// Returns the items in the same order as the repos
+// On the first error, the repos that have not been fetched yet are skipped
+func fetchPerRepo(ctx context.Context, repos []Repo, fetch func(ctx context.Context, r Repo) ([]Item, error)) ([]Item, error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]Item, len(repos))
+	sem := make(chan struct{}, MAX_CONCURRENT_REQUESTS)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i, r := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			items, err := fetch(ctx, r)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("%s: %s", r, err.Error())
+					cancel()
+				})
+				return
+			}
+			results[i] = items
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return []Item{}, firstErr
+	}
+	if err := parent.Err(); err != nil {
+		return []Item{}, err
+	}
+	return slices.Concat(results...), nil
+}
+
+// Gets the PRs and issues of all repos with one GraphQL query per host and
+// token, instead of one request per repo
+func fetchViaGraphQL(ctx context.Context, repos []Repo, tokens map[string]string, opts github.GraphQLOptions, httpClient *http.Client) (map[Repo]github.RepoContents, error) {
+	type group struct {
+		BaseURL string
+		Token   string
+	}
+	var groups []group
+	groupRepos := map[group][]Repo{}
+	for _, r := range repos {
+		g := group{BaseURL: r.BaseURL, Token: r.githubToken(tokens)}
+		if _, ok := groupRepos[g]; !ok {
+			groups = append(groups, g)
+		}
+		groupRepos[g] = append(groupRepos[g], r)
+	}
+	contents := map[Repo]github.RepoContents{}
+	for _, g := range groups {
+		var refs []github.RepoRef
+		for _, r := range groupRepos[g] {
+			refs = append(refs, github.RepoRef{Owner: r.Owner, Name: r.Name})
+		}
+		groupContents, err := github.Client{BaseURL: g.BaseURL, Token: g.Token, HTTPClient: httpClient}.ListAll(ctx, refs, opts)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the repos from %s: %s", g.BaseURL, err.Error())
+		}
+		for i, r := range groupRepos[g] {
+			contents[r] = groupContents[i]
+		}
+	}
+	return contents, nil
+}
+
+func getPrs(getRepos func(ctx context.Context) ([]Repo, error), tokens map[string]string, hideDrafts bool, prState string, checks bool, ignoreAuthors []string, useGraphQL bool, httpClient *http.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		repos, err := getRepos(ctx)
+		if err != nil {
+			return []Item{}, err
+		}
+		var contents map[Repo]github.RepoContents
+		if useGraphQL {
+			contents, err = fetchViaGraphQL(ctx, repos, tokens, github.GraphQLOptions{PRState: prState}, httpClient)
+			if err != nil {
+				return []Item{}, fmt.Errorf("Failed to list PRs: %s", err.Error())
+			}
+		}
+		return fetchPerRepo(ctx, repos, func(ctx context.Context, r Repo) ([]Item, error) {
+			prs := contents[r].PRs
+			if !useGraphQL {
+				var err error
+				prs, err = r.client(tokens, httpClient).ListPRs(ctx, r.Owner, r.Name, prState)
+				if err != nil {
+					return []Item{}, fmt.Errorf("Failed to list PRs: %s", err.Error())
+				}
+			}
+			var items []Item
+			for _, pr := range prs {
+				if hideDrafts && pr.Draft {
+					continue
+				}
+				if isIgnoredAuthor(pr.User.Login, ignoreAuthors) {
+					continue
+				}
+				value := fmt.Sprintf("#%d: %s (%s)", pr.Number, pr.Title, pr.User.Login)
+				var color rl.Color
+				if pr.MergedAt != nil {
+					value = "[merged] " + value
+					color = COLOR_MUTED
+				} else if pr.State == "closed" {
+					value = "[closed] " + value
+					color = COLOR_MUTED
+				}
+				if checks {
+					runs, err := r.client(tokens, httpClient).ListCheckRuns(ctx, r.Owner, r.Name, pr.Head.SHA)
+					if err != nil {
+						return []Item{}, fmt.Errorf("Failed to get checks for PR: %s", err.Error())
+					}
+					if symbol := checksSymbol(runs); symbol != "" {
+						value = fmt.Sprintf("%s %s", symbol, value)
+					}
+				}
+				items = append(items, Item{
+					Value:     value,
+					URL:       pr.HtmlURL,
+					Color:     color,
+					CreatedAt: pr.CreatedAt,
+					Source:    r.String(),
+					RepoPage:  r.webURL() + "/pulls",
+				})
+			}
+			return items, nil
+		})
+	}
+}
+
+func getReviewRequestedPRs(client github.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		prs, err := client.ListReviewRequestedPRs(ctx, "")
+		if err != nil {
+			return []Item{}, fmt.Errorf("Failed to list review requested PRs: %s", err.Error())
+		}
+		var items []Item
+		for _, pr := range prs {
+			items = append(items, Item{
+				Value:     fmt.Sprintf("#%d: %s", pr.Number, pr.Title),
+				URL:       pr.HtmlURL,
+				CreatedAt: pr.CreatedAt,
+				Source:    pr.Repo(),
+				RepoPage:  repoURLOf(pr.HtmlURL, "/pulls"),
+			})
+		}
+		return items, nil
+	}
+}
+
+func getSearchResults(client github.Client, query string) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		issues, err := client.SearchIssues(ctx, query)
+		if err != nil {
+			return []Item{}, err
+		}
+		var items []Item
+		for _, issue := range issues {
+			// The results are PRs, issues or both
+			page := "/issues"
+			if issue.PullRequest.URL != "" {
+				page = "/pulls"
+			}
+			items = append(items, Item{
+				Value:     fmt.Sprintf("#%d: %s", issue.Number, issue.Title),
+				URL:       issue.HtmlURL,
+				CreatedAt: issue.CreatedAt,
+				Source:    issue.Repo(),
+				RepoPage:  repoURLOf(issue.HtmlURL, page),
+			})
+		}
+		return items, nil
+	}
+}
+
+func getNotifications(client github.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		notifications, err := client.ListNotifications(ctx)
+		if err != nil {
+			return []Item{}, err
+		}
+		var items []Item
+		for _, n := range notifications {
+			items = append(items, Item{
+				Value:     fmt.Sprintf("%s (%s)", n.Subject.Title, strings.ReplaceAll(n.Reason, "_", " ")),
+				URL:       n.HtmlURL(),
+				CreatedAt: n.UpdatedAt,
+				Source:    n.Repository.FullName,
+				RepoPage:  n.Repository.HtmlURL,
+			})
+		}
+		return items, nil
+	}
+}
+
+func getIssues(getRepos func(ctx context.Context) ([]Repo, error), tokens map[string]string, labels []string, since time.Duration, useGraphQL bool, httpClient *http.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		repos, err := getRepos(ctx)
+		if err != nil {
+			return []Item{}, err
+		}
+		var updatedSince time.Time
+		if since > 0 {
+			// Rounded so that the url stays the same for a while, which
+			// lets the responses be cached
+			updatedSince = time.Now().Add(-since).Truncate(time.Hour)
+		}
+		var contents map[Repo]github.RepoContents
+		if useGraphQL {
+			contents, err = fetchViaGraphQL(ctx, repos, tokens, github.GraphQLOptions{IssueLabels: labels, IssuesSince: updatedSince}, httpClient)
+			if err != nil {
+				return []Item{}, fmt.Errorf("Failed to list issues: %s", err.Error())
+			}
+		}
+		return fetchPerRepo(ctx, repos, func(ctx context.Context, r Repo) ([]Item, error) {
+			issues := contents[r].Issues
+			if !useGraphQL {
+				var err error
+				issues, err = r.client(tokens, httpClient).ListIssues(ctx, r.Owner, r.Name, labels, updatedSince)
+				if errors.Is(err, github.ErrDisabled) || errors.Is(err, github.ErrNotFound) {
+					noteSkipped("issues", r, err)
+					return []Item{}, nil
+				}
+				if err != nil {
+					return []Item{}, fmt.Errorf("Failed to list issues: %s", err.Error())
+				}
+			}
+			var items []Item
+			for _, issue := range issues {
+				items = append(items, Item{
+					Value:     fmt.Sprintf("#%d: %s", issue.Number, issue.Title),
+					URL:       issue.HtmlURL,
+					CreatedAt: issue.CreatedAt,
+					Source:    r.String(),
+					RepoPage:  r.webURL() + "/issues",
+				})
+			}
+			return items, nil
+		})
+	}
+}
+
+// The features that have been noted as skipped, like "issues owner/name"
+var notedSkipped sync.Map
+
+// Logs that a feature is disabled or not found for a repo, which is then
+// skipped quietly
+// Only the first time is logged, since it happens on every refresh
+func noteSkipped(feature string, r Repo, reason error) {
+	if _, noted := notedSkipped.LoadOrStore(fmt.Sprintf("%s %s", feature, r), true); !noted {
+		fmt.Printf("Skipping %s for %s: %s\n", feature, r, reason.Error())
+	}
+}
+
+// Formats how long ago t was, like "3d ago", "5h ago" or "just now"
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	if d < time.Minute {
+		return "just now"
+	}
+	return fmt.Sprintf("%s ago", shortDuration(d))
+}
+
+// Returns the duration in whole minutes, hours or days, like 5m or 2h
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func getAssignedIssues(getRepos func(ctx context.Context) ([]Repo, error), tokens map[string]string, httpClient *http.Client) func(ctx context.Context) ([]Item, error) {
+	type account struct {
+		BaseURL string
+		Token   string
+	}
+	// The user of a token does not change, so it is only looked up once per
+	// host and token, instead of for every repo on every refresh
+	logins := map[account]string{}
+	return func(ctx context.Context) ([]Item, error) {
+		repos, err := getRepos(ctx)
+		if err != nil {
+			return []Item{}, err
+		}
+		for _, r := range repos {
+			a := account{BaseURL: r.BaseURL, Token: r.githubToken(tokens)}
+			if _, ok := logins[a]; ok {
+				continue
+			}
+			user, err := github.Client{BaseURL: a.BaseURL, Token: a.Token, HTTPClient: httpClient}.AuthenticatedUser(ctx)
+			if err != nil {
+				return []Item{}, err
+			}
+			logins[a] = user.Login
+		}
+		return fetchPerRepo(ctx, repos, func(ctx context.Context, r Repo) ([]Item, error) {
+			login := logins[account{BaseURL: r.BaseURL, Token: r.githubToken(tokens)}]
+			issues, err := r.client(tokens, httpClient).ListIssuesAssignedTo(ctx, r.Owner, r.Name, login)
+			if errors.Is(err, github.ErrDisabled) || errors.Is(err, github.ErrNotFound) {
+				noteSkipped("issues", r, err)
+				return []Item{}, nil
+			}
+			if err != nil {
+				return []Item{}, fmt.Errorf("Failed to list assigned issues: %s", err.Error())
+			}
+			var items []Item
+			for _, issue := range issues {
+				items = append(items, Item{
+					Value:     fmt.Sprintf("#%d: %s", issue.Number, issue.Title),
+					URL:       issue.HtmlURL,
+					CreatedAt: issue.CreatedAt,
+					Source:    r.String(),
+					RepoPage:  r.webURL() + "/issues",
+				})
+			}
+			return items, nil
+		})
+	}
+}
+
+type Silence struct {
+	Matchers  []SilenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Creates a silence in Alertmanager that matches all labels of an alert
+func silenceAlert(alertsConfig AlertsConfig, httpClient *http.Client) func(ctx context.Context, labels map[string]string, d time.Duration) error {
+	return func(ctx context.Context, labels map[string]string, d time.Duration) error {
+		silence := Silence{
+			StartsAt:  time.Now(),
+			EndsAt:    time.Now().Add(d),
+			CreatedBy: PROGRAM_NAME,
+			Comment:   fmt.Sprintf("Silenced from %s", PROGRAM_NAME),
+		}
+		for name, value := range labels {
+			silence.Matchers = append(silence.Matchers, SilenceMatcher{Name: name, Value: value, IsEqual: true})
+		}
+		body, err := json.Marshal(silence)
+		if err != nil {
+			return fmt.Errorf("Could not encode silence: %s", err.Error())
+		}
+		url := fmt.Sprintf("%s/api/v2/silences", alertsConfig.Server)
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("Could not create silence request: %s", err.Error())
+		}
+		req.Header.Add("Content-Type", "application/json")
+		alertsConfig.authorize(req)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("Could not create silence: %s", err.Error())
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("Got non-200 status code when creating silence: %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+type Alert struct {
+	Annotations struct {
+		Description string `json:"description"`
+	} `json:"annotations"`
+	Labels   map[string]string `json:"labels"`
+	StartsAt time.Time         `json:"startsAt"`
+}
+
+// Lower is more severe, unknown severities are the least severe
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 0
+	case "warning":
+		return 1
+	case "info":
+		return 2
+	default:
+		return 3
+	}
+}
+
+func getAlerts(alertsConfig AlertsConfig, httpClient *http.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		var alerts []Alert
+		query := fmt.Sprintf("receiver=%s&silenced=false&inhibited=false", url.QueryEscape(alertsConfig.Receiver))
+		apiQuery := query
+		for _, filter := range alertsConfig.Filters {
+			apiQuery += fmt.Sprintf("&filter=%s", url.QueryEscape(filter))
+		}
+		// The web UI takes all the matchers in a single filter
+		uiQuery := query
+		if len(alertsConfig.Filters) > 0 {
+			uiQuery += fmt.Sprintf("&filter=%s", url.QueryEscape("{"+strings.Join(alertsConfig.Filters, ",")+"}"))
+		}
+		url := fmt.Sprintf("%s/api/v2/alerts?%s", alertsConfig.Server, apiQuery)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return []Item{}, fmt.Errorf("Could not create alerts request: %s", err.Error())
+		}
+		alertsConfig.authorize(req)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return []Item{}, fmt.Errorf("Could not get alerts: %s\n", err.Error())
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return []Item{}, fmt.Errorf("Got non-200 status code when getting alerts: %s\n", resp.Status)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+			return []Item{}, fmt.Errorf("Could not parse alerts response: %s", err.Error())
+		}
+		// Most severe first, then the most recent first
+		slices.SortFunc(alerts, func(a, b Alert) int {
+			if rank := severityRank(a.Labels["severity"]) - severityRank(b.Labels["severity"]); rank != 0 {
+				return rank
+			}
+			return -1 * a.StartsAt.Compare(b.StartsAt)
+		})
+		var items []Item
+		for _, a := range alerts {
+			var color rl.Color
+			if a.Labels["severity"] == "critical" {
+				color = COLOR_CRITICAL
+			} else if time.Since(a.StartsAt) > LONG_FIRING_ALERT {
+				color = COLOR_LONG_FIRING
+			}
+			items = append(items, Item{
+				Value:     fmt.Sprintf("[%s] %s: %s", a.Labels["severity"], a.Labels["alertname"], a.Annotations.Description),
+				URL:       fmt.Sprintf("%s/#/alerts?%s", alertsConfig.Server, uiQuery),
+				Color:     color,
+				CreatedAt: a.StartsAt,
+				Source:    "alertmanager",
+				Labels:    a.Labels,
+			})
+		}
+		return items, nil
+	}
+}
+
+type itemSource struct {
+	Name     string
+	GetItems func(ctx context.Context) ([]Item, error)
+}
+
+// Returns the items of all sources, with the most recent first and the name of
+// the source in front of each item
+// Returns a getter that shares the fetches of getter between the tabs that use
+// it, so that the items are fetched once instead of once per tab
+// A call while getter is running for the same item limit waits for it and gets
+// its result, which works since the tabs are fetched at the same time
+func sharedGetter(getter func(ctx context.Context) ([]Item, error)) func(ctx context.Context) ([]Item, error) {
+	type fetch struct {
+		maxItems int
+		done     chan struct{}
+		items    []Item
+		err      error
+	}
+	var mu sync.Mutex
+	var running *fetch
+	return func(ctx context.Context) ([]Item, error) {
+		mu.Lock()
+		f := running
+		if f == nil || f.maxItems != github.MaxItems(ctx) {
+			f = &fetch{maxItems: github.MaxItems(ctx), done: make(chan struct{})}
+			running = f
+			mu.Unlock()
+			f.items, f.err = getter(ctx)
+			mu.Lock()
+			if running == f {
+				running = nil
+			}
+			close(f.done)
+		}
+		mu.Unlock()
+		<-f.done
+		return slices.Clone(f.items), f.err
+	}
+}
+
+func getAll(sources []itemSource) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		var items []Item
+		for _, source := range sources {
+			sourceItems, err := source.GetItems(ctx)
+			if err != nil {
+				return []Item{}, err
+			}
+			for _, item := range sourceItems {
+				item.Value = fmt.Sprintf("%s: %s", source.Name, item.Value)
+				items = append(items, item)
+			}
+		}
+		slices.SortStableFunc(items, func(a, b Item) int {
+			return -1 * a.CreatedAt.Compare(b.CreatedAt)
+		})
+		return items, nil
+	}
+}
+
+func getWorkflowRuns(getRepos func(ctx context.Context) ([]Repo, error), tokens map[string]string, latestOnly bool, count int, httpClient *http.Client) func(ctx context.Context) ([]Item, error) {
+	return func(ctx context.Context) ([]Item, error) {
+		repos, err := getRepos(ctx)
+		if err != nil {
+			return []Item{}, err
+		}
+		return fetchPerRepo(ctx, repos, func(ctx context.Context, r Repo) ([]Item, error) {
+			runs, err := r.client(tokens, httpClient).ListWorkflowRuns(ctx, r.Owner, r.Name, count)
+			if errors.Is(err, github.ErrDisabled) || errors.Is(err, github.ErrNotFound) {
+				noteSkipped("workflow runs", r, err)
+				return []Item{}, nil
+			}
+			if err != nil {
 				return []Item{}, fmt.Errorf("Failed to list workflow runs: %s", err.Error())
 			}
+			if latestOnly {
+				runs = latestRunPerWorkflow(runs)
+			}
+			var items []Item
 			for _, run := range runs {
+				// Runs that have not finished yet have no conclusion
+				status := run.Conclusion
+				if status == "" {
+					status = run.Status
+				}
 				items = append(items, Item{
-					Value: fmt.Sprintf("[%s] %s: %s", run.Conclusion, r, run.Name),
-					URL:   run.HtmlURL,
+					Value:     fmt.Sprintf("[%s] %s (%s, %s)", status, run.Name, run.HeadBranch, run.Event),
+					URL:       run.HtmlURL,
+					Color:     workflowRunColor(run),
+					CreatedAt: run.CreatedAt,
+					Source:    r.String(),
+					RepoPage:  r.webURL() + "/actions",
+					Status:    status,
 				})
 			}
+			return items, nil
+		})
+	}
+}
+
+// Returns the most recent run of each workflow, most recent first
+func latestRunPerWorkflow(runs []github.WorkflowRun) []github.WorkflowRun {
+	runs = slices.Clone(runs)
+	slices.SortStableFunc(runs, func(a, b github.WorkflowRun) int {
+		return -1 * a.CreatedAt.Compare(b.CreatedAt)
+	})
+	seen := make(map[string]bool)
+	var latest []github.WorkflowRun
+	for _, run := range runs {
+		if !seen[run.Name] {
+			seen[run.Name] = true
+			latest = append(latest, run)
+		}
+	}
+	return latest
+}
+
+// Returns true if login is one of the ignored authors, where an author like
+// *[bot] matches all logins that end with [bot]
+func isIgnoredAuthor(login string, ignoreAuthors []string) bool {
+	for _, author := range ignoreAuthors {
+		if suffix, isPattern := strings.CutPrefix(author, "*"); isPattern && strings.HasSuffix(login, suffix) {
+			return true
+		}
+		if login == author {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a symbol for the combined result of the check runs, or the empty
+// string if there are none
+func checksSymbol(runs []github.CheckRun) string {
+	if len(runs) == 0 {
+		return ""
+	}
+	pending := false
+	for _, run := range runs {
+		if slices.Contains(FAILED_CONCLUSIONS, run.Conclusion) {
+			return "✗"
+		}
+		if run.Status != "completed" {
+			pending = true
+		}
+	}
+	if pending {
+		return "●"
+	}
+	return "✓"
+}
+
+// Returns true if any of the workflow run items did not succeed
+func hasFailedRun(items []Item) bool {
+	return slices.ContainsFunc(items, func(item Item) bool {
+		return slices.Contains(FAILED_CONCLUSIONS, item.Status)
+	})
+}
+
+func workflowRunColor(run github.WorkflowRun) rl.Color {
+	switch {
+	case run.Conclusion == "failure":
+		return COLOR_FAILURE
+	case run.Conclusion == "success":
+		return COLOR_SUCCESS
+	case run.Conclusion == "" && run.Status == "in_progress":
+		return COLOR_IN_PROGRESS
+	default:
+		return rl.Color{}
+	}
+}
+
+func reactToInput(state *State, keybindings map[string][]Keybinding, confirmQuit bool) {
+	if state.Filtering {
+		reactToFilterInput(state)
+		return
+	}
+	if state.Silencing {
+		reactToSilenceInput(state)
+		return
+	}
+	rows := visibleRows(state.TabDisplays[state.SelectedTab].DisplayMode)
+	switch applyKey(state, pressedKeybinding(rl.GetKeyPressed()), keybindings, confirmQuit, rows) {
+	case "open":
+		openApplication(*state)
+	case "openrepo":
+		openRepoPage(*state)
+	case "openall":
+		openAll(*state)
+	case "copy":
+		copyURL(*state)
+	}
+	if state.Filtering || state.Silencing {
+		// Drop the key that started the typing, which is also queued as a
+		// character
+		for rl.GetCharPressed() != 0 {
 		}
-		return items, nil
 	}
 }
 
-func reactToInput(state *State) {
+// Updates the state for a pressed key without calling raylib, so that it works
+// without a window, where rows is how many items fit in the body
+// Returns the action if it has to be done outside of the state, which is open,
+// openrepo, openall or copy, and the empty string otherwise
+func applyKey(state *State, pressed Keybinding, keybindings map[string][]Keybinding, confirmQuit bool, rows int) string {
+	key := pressed.Key
+	if state.ConfirmOpenAll {
+		if key == 0 {
+			return ""
+		}
+		state.ConfirmOpenAll = false
+		if key == rl.KeyY {
+			return "openall"
+		}
+		return ""
+	}
+	if state.ConfirmQuit {
+		if key == 0 {
+			return ""
+		}
+		state.ConfirmQuit = false
+		if key == rl.KeyY || slices.Contains(keybindings["quit"], pressed) {
+			state.ShouldClose = true
+		}
+		return ""
+	}
 	gotInput := true
-	nItems := len(state.TabData[state.SelectedTab].Items)
-	switch rl.GetKeyPressed() {
-	case rl.KeyLeft, rl.KeyA, rl.KeyH:
-		tabIdx := slices.Index(state.TabIDs, state.SelectedTab)
+	action := ""
+	nItems := len(state.visibleItems(state.SelectedTab))
+	isBound := func(action string) bool {
+		return slices.Contains(keybindings[action], pressed)
+	}
+	// A digit is either a count for the next motion or a tab to switch to,
+	// which is decided by the key that comes after it
+	if digit := int(key - rl.KeyZero); !pressed.Shift && !pressed.Ctrl && digit >= 0 && digit <= 9 && (digit > 0 || state.PendingCount > 0) {
+		state.PendingCount = state.PendingCount*10 + digit
+		state.PendingCountAt = time.Now()
+		return ""
+	}
+	isMotion := isBound("up") || isBound("down") || isBound("pageup") || isBound("pagedown")
+	timedOut := key == 0 && time.Since(state.PendingCountAt) > PENDING_COUNT_TIMEOUT
+	count := max(1, state.PendingCount)
+	if state.PendingCount > 0 && key == rl.KeyEscape {
+		state.PendingCount = 0
+		return ""
+	}
+	if state.PendingCount > 0 && !isMotion && (key != 0 || timedOut) {
+		if tabs := state.visibleTabs(); state.PendingCount <= min(9, len(tabs)) {
+			state.SelectedTab = tabs[state.PendingCount-1]
+			state.markViewed(state.SelectedTab)
+		}
+		count = 1
+	}
+	if key != 0 || timedOut {
+		state.PendingCount = 0
+	}
+	switch {
+	case key == 0:
+		gotInput = false
+	case isBound("left"):
+		tabs := state.visibleTabs()
+		tabIdx := slices.Index(tabs, state.SelectedTab)
 		newTabIdx := max(0, tabIdx-1)
 		if newTabIdx != tabIdx {
-			state.SelectedTab = state.TabIDs[newTabIdx]
+			state.SelectedTab = tabs[newTabIdx]
 		}
-	case rl.KeyRight, rl.KeyD, rl.KeyL:
-		tabIdx := slices.Index(state.TabIDs, state.SelectedTab)
-		newTabIdx := min(len(state.TabIDs)-1, tabIdx+1)
+	case isBound("right"):
+		tabs := state.visibleTabs()
+		tabIdx := slices.Index(tabs, state.SelectedTab)
+		newTabIdx := min(len(tabs)-1, tabIdx+1)
 		if newTabIdx != tabIdx {
-			state.SelectedTab = state.TabIDs[newTabIdx]
+			state.SelectedTab = tabs[newTabIdx]
 		}
-	case rl.KeyUp, rl.KeyW, rl.KeyK:
+	case isBound("up"):
 		tab := state.TabDisplays[state.SelectedTab]
-		tab.SelectedItem = max(0, state.TabDisplays[state.SelectedTab].SelectedItem-1)
+		tab.SelectedItem = max(0, state.TabDisplays[state.SelectedTab].SelectedItem-count)
 		state.TabDisplays[state.SelectedTab] = tab
-	case rl.KeyDown, rl.KeyS, rl.KeyJ:
+	case isBound("down"):
 		tab := state.TabDisplays[state.SelectedTab]
-		tab.SelectedItem = min(nItems-1, state.TabDisplays[state.SelectedTab].SelectedItem+1)
+		tab.SelectedItem = max(0, min(nItems-1, state.TabDisplays[state.SelectedTab].SelectedItem+count))
 		state.TabDisplays[state.SelectedTab] = tab
-	case rl.KeyEnter, rl.KeySpace:
-		openApplication(*state)
-	case rl.KeyOne:
-		state.SelectedTab = state.TabIDs[0]
-	case rl.KeyTwo:
-		state.SelectedTab = state.TabIDs[1]
-	case rl.KeyThree:
-		state.SelectedTab = state.TabIDs[2]
-	case rl.KeyFour:
-		state.SelectedTab = state.TabIDs[3]
-	case rl.KeyQ:
-		state.ShouldClose = true
+	case isBound("pageup"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.SelectedItem = max(0, tab.SelectedItem-count*rows)
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("pagedown"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.SelectedItem = max(0, min(nItems-1, tab.SelectedItem+count*rows))
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("sort"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.SortMode = (tab.SortMode + 1) % (SORT_ALPHABETICAL + 1)
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("details"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.DisplayMode = (tab.DisplayMode + 1) % (DISPLAY_DETAILED + 1)
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("top"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.SelectedItem = 0
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("bottom"):
+		tab := state.TabDisplays[state.SelectedTab]
+		tab.SelectedItem = max(0, nItems-1)
+		state.TabDisplays[state.SelectedTab] = tab
+	case isBound("open"):
+		action = "open"
+	case isBound("openrepo"):
+		action = "openrepo"
+	case isBound("openall"):
+		if len(uniqueURLs(state.visibleItems(state.SelectedTab))) > OPEN_ALL_CONFIRM_THRESHOLD {
+			state.ConfirmOpenAll = true
+		} else {
+			action = "openall"
+		}
+	case isBound("copy"):
+		action = "copy"
+	case isBound("more"):
+		if state.TabData[state.SelectedTab].MaxItems > 0 {
+			// Drop the key if the updater has not taken the previous one yet
+			select {
+			case state.TabData[state.SelectedTab].LoadMore <- struct{}{}:
+			default:
+			}
+		}
+	case isBound("silence"):
+		items := state.visibleItems(state.SelectedTab)
+		if state.TabData[state.SelectedTab].Silence != nil && len(items) > 0 && items[state.TabDisplays[state.SelectedTab].SelectedItem].Labels != nil {
+			state.Silencing = true
+			state.SilenceInput = ""
+		}
+	case isBound("markread"):
+		// Every handled key marks the tab as viewed, so there is nothing more to do
+	case key == rl.KeySlash:
+		state.Filtering = true
+	case key == rl.KeyEscape:
+		setFilter(state, "")
+	case isBound("quit"):
+		if confirmQuit {
+			state.ConfirmQuit = true
+		} else {
+			state.ShouldClose = true
+		}
 	default:
 		gotInput = false
 	}
 	if gotInput {
-		tab := state.TabDisplays[state.SelectedTab]
-		tab.LastViewedAt = time.Now()
+		state.markViewed(state.SelectedTab)
+	}
+	return action
+}
+
+// Select tabs by clicking the headers, open items by clicking them and move
+// the selection with the scroll wheel
+func reactToMouse(state *State) {
+	gotInput := false
+	mouse := rl.GetMousePosition()
+	tab := state.TabDisplays[state.SelectedTab]
+	nItems := len(state.visibleItems(state.SelectedTab))
+	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+		tabs := state.visibleTabs()
+		for i, rect := range getHeaderRects(len(tabs)) {
+			if rl.CheckCollisionPointRec(mouse, rect) {
+				state.SelectedTab = tabs[i]
+				gotInput = true
+			}
+		}
+		for i := tab.ScrollOffset; i < min(nItems, tab.ScrollOffset+visibleRows(tab.DisplayMode)); i++ {
+			if rl.CheckCollisionPointRec(mouse, getItemRect(i-tab.ScrollOffset, tab.DisplayMode)) {
+				tab.SelectedItem = i
+				state.TabDisplays[state.SelectedTab] = tab
+				openApplication(*state)
+				gotInput = true
+			}
+		}
+	}
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 && nItems > 0 {
+		// Scrolling down gives a negative wheel move
+		step := -1
+		if wheel < 0 {
+			step = 1
+		}
+		tab.SelectedItem = max(0, min(nItems-1, tab.SelectedItem+step))
 		state.TabDisplays[state.SelectedTab] = tab
+		gotInput = true
+	}
+	if gotInput {
+		state.markViewed(state.SelectedTab)
+	}
+}
+
+// Edit the filter of the selected tab, until enter or escape is pressed
+func reactToFilterInput(state *State) {
+	tab := state.TabDisplays[state.SelectedTab]
+	filter := tab.Filter
+	for char := rl.GetCharPressed(); char != 0; char = rl.GetCharPressed() {
+		filter += string(char)
+	}
+	switch rl.GetKeyPressed() {
+	case rl.KeyBackspace:
+		runes := []rune(filter)
+		filter = string(runes[:max(0, len(runes)-1)])
+	case rl.KeyEnter:
+		state.Filtering = false
+	case rl.KeyEscape:
+		state.Filtering = false
+		filter = ""
+	}
+	if filter != tab.Filter {
+		setFilter(state, filter)
+	}
+}
+
+// Read how long to silence the selected alert for, and silence it on enter
+func reactToSilenceInput(state *State) {
+	for char := rl.GetCharPressed(); char != 0; char = rl.GetCharPressed() {
+		state.SilenceInput += string(char)
+	}
+	switch rl.GetKeyPressed() {
+	case rl.KeyBackspace:
+		runes := []rune(state.SilenceInput)
+		state.SilenceInput = string(runes[:max(0, len(runes)-1)])
+	case rl.KeyEnter:
+		d, err := time.ParseDuration(state.SilenceInput)
+		if err != nil || d <= 0 {
+			// Keep asking until the duration is correct or cancelled
+			return
+		}
+		state.Silencing = false
+		items := state.visibleItems(state.SelectedTab)
+		if len(items) == 0 {
+			return
+		}
+		item := items[state.TabDisplays[state.SelectedTab].SelectedItem]
+		silence := state.TabData[state.SelectedTab].Silence
+		// Don't block drawing while the request is made
+		go func() {
+			if err := silence(context.Background(), item.Labels, d); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not silence %s: %s\n", item.Value, err.Error())
+				return
+			}
+			fmt.Printf("Silenced %s for %s\n", item.Value, d)
+		}()
+	case rl.KeyEscape:
+		state.Silencing = false
+	}
+}
+
+// Set the filter of the selected tab, clamping the selection to the filtered items
+func setFilter(state *State, filter string) {
+	tab := state.TabDisplays[state.SelectedTab]
+	tab.Filter = filter
+	state.TabDisplays[state.SelectedTab] = tab
+	nItems := len(state.visibleItems(state.SelectedTab))
+	tab.SelectedItem = max(0, min(tab.SelectedItem, nItems-1))
+	state.TabDisplays[state.SelectedTab] = tab
+	state.markViewed(state.SelectedTab)
+}
+
+func copyURL(state State) {
+	items := state.visibleItems(state.SelectedTab)
+	if len(items) == 0 {
+		return
+	}
+	item := items[state.TabDisplays[state.SelectedTab].SelectedItem]
+	if item.URL != "" {
+		rl.SetClipboardText(item.URL)
 	}
 }
 
 func openApplication(state State) {
+	items := state.visibleItems(state.SelectedTab)
 	// TODO: Default app or url to open when there are no items?
-	if len(state.TabData[state.SelectedTab].Items) == 0 {
+	if len(items) == 0 {
+		return
+	}
+	openItem(items[state.TabDisplays[state.SelectedTab].SelectedItem])
+}
+
+// Open the page of the selected item's repo that lists items like it, like
+// the repo's actions for a workflow run
+func openRepoPage(state State) {
+	items := state.visibleItems(state.SelectedTab)
+	if len(items) == 0 {
+		return
+	}
+	item := items[state.TabDisplays[state.SelectedTab].SelectedItem]
+	if item.RepoPage != "" {
+		openItem(Item{Value: item.Label(), URL: item.RepoPage})
+	}
+}
+
+// Open all the visible items of the selected tab, once per url
+func openAll(state State) {
+	for _, item := range uniqueURLs(state.visibleItems(state.SelectedTab)) {
+		openItem(item)
+	}
+}
+
+// Returns the items with a url that is not the same as an earlier item's, in
+// case several items link to the same page like alerts do
+func uniqueURLs(items []Item) []Item {
+	var unique []Item
+	for i, item := range items {
+		if item.URL == "" || !slices.ContainsFunc(items[:i], func(other Item) bool { return other.URL == item.URL }) {
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
+
+func openItem(item Item) {
+	cmd, err := openCommand(item)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open %s: %s\n", item.Label(), err.Error())
+		return
+	}
+	if cmd == nil {
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open %s: %s\n", item.Label(), err.Error())
 		return
 	}
-	item := state.TabData[state.SelectedTab].Items[state.TabDisplays[state.SelectedTab].SelectedItem]
-	if item.Application != "" {
-		cmd := exec.Command("open", "-a", item.Application)
-		cmd.Run()
-	} else if item.URL != "" {
-		rl.OpenURL(item.URL)
+	go cmd.Wait()
+}
+
+// Returns the command that launches the item's application or opens its url
+// Returns nil if the item has neither
+func openCommand(item Item) (*exec.Cmd, error) {
+	var args []string
+	switch {
+	case item.Application != "" && runtime.GOOS == "darwin":
+		args = []string{"open", "-a", item.Application}
+	case item.Application != "" && runtime.GOOS == "windows":
+		args = []string{"cmd", "/c", "start", "", item.Application}
+	case item.Application != "":
+		args = []string{item.Application}
+	case item.URL != "" && runtime.GOOS == "darwin":
+		args = []string{"open", item.URL}
+	case item.URL != "" && runtime.GOOS == "windows":
+		args = []string{"rundll32", "url.dll,FileProtocolHandler", item.URL}
+	case item.URL != "":
+		args = []string{"xdg-open", item.URL}
+	default:
+		return nil, nil
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return nil, err
 	}
+	return exec.Command(path, args[1:]...), nil
 }
 
 func drawWindowTitle(state *State) {
@@ -394,40 +2651,72 @@ func drawWindowTitle(state *State) {
 }
 
 func drawHeaders(state State, font rl.Font, fontSize float32) {
-	rects := getHeaderRects(len(state.TabIDs))
-	for i, tabID := range state.TabIDs {
+	tabs := state.visibleTabs()
+	rects := getHeaderRects(len(tabs))
+	for i, tabID := range tabs {
+		display := state.TabDisplays[tabID]
 		if tabID == state.SelectedTab {
-			rl.DrawRectangleRounded(rects[i], 1, 1, COLOR_SELECTED_HEADER)
+			highlight := COLOR_SELECTED_HEADER
+			if display.Style.Accent != (rl.Color{}) {
+				highlight = display.Style.Accent
+			}
+			rl.DrawRectangleRounded(rects[i], 1, 1, highlight)
 		}
 		nItems := len(state.TabData[tabID].Items)
 		notice := ""
+		added := ""
 
-		if state.TabDisplays[tabID].LastViewedAt.Before(state.TabData[tabID].ModifiedAt) {
+		if display.LastViewedAt.Before(state.TabData[tabID].ModifiedAt) {
 			notice = "*"
+			// Tabs that have never been viewed have nothing to compare with
+			if n := len(newItems(display.ViewedItems, state.TabData[tabID].Items)); n > 0 && !display.LastViewedAt.IsZero() {
+				added = fmt.Sprintf(" (+%d)", n)
+			}
+		}
+		if state.TabData[tabID].Err != nil {
+			notice = "!" + notice
 		}
-		text := fmt.Sprintf("%s%s [%d]", notice, state.TabDisplays[tabID].Title, nItems)
-		textWidth := rl.MeasureText(text, int32(FONT_SIZE_HEADER))
-		padX := (rects[i].Width - float32(textWidth)) / 2
+		count := fmt.Sprint(nItems)
+		if state.TabData[tabID].Loading && nItems == 0 {
+			count = "..."
+		}
+		title := display.Title
+		if display.Style.Icon != "" {
+			title = fmt.Sprintf("%s %s", display.Style.Icon, title)
+		}
+		text := fmt.Sprintf("%s%s [%s]%s", notice, title, count, added)
+		textWidth := rl.MeasureTextEx(font, text, fontSize, 0).X
+		padX := (rects[i].Width - textWidth) / 2
 		rl.DrawTextEx(font, text, rl.NewVector2(rects[i].X+padX, rects[i].Y), fontSize, 0, COLOR_HEADER)
 	}
 }
 
 // Send a desktop notification if any of the tab's data was updated
 // after the last notification was sent for that tab
-func notifyIfNeeded(state *State) {
+func notifyIfNeeded(state *State, notifyTabs []string) {
 	for _, tabID := range state.TabIDs {
 		sentAt := state.NotificationSentAt[tabID]
 		modifiedAt := state.TabData[tabID].ModifiedAt
+		items := state.TabData[tabID].Items
 		if sentAt.IsZero() {
 			// Do not send a notification the first time the data has been
 			// updated, since this happens at startup
 			state.NotificationSentAt[tabID] = modifiedAt
+			state.NotifiedItems[tabID] = items
 		} else {
 			if sentAt.Before(modifiedAt) {
+				added := newItems(state.NotifiedItems[tabID], items)
 				state.NotificationSentAt[tabID] = modifiedAt
-				if err := Notify(state.TabDisplays[tabID].Title); err != nil {
+				state.NotifiedItems[tabID] = items
+				if notifyTabs != nil && !slices.Contains(notifyTabs, tabID) {
+					continue
+				}
+				if shouldNotify := state.TabData[tabID].ShouldNotify; shouldNotify != nil && !shouldNotify(added) {
+					continue
+				}
+				msg := notificationMessage(state.TabDisplays[tabID].Title, added)
+				if err := Notify(PROGRAM_NAME, msg); err != nil {
 					fmt.Fprintf(os.Stderr, "Failed to create notification: %s\n", err.Error())
-					os.Exit(1)
 				}
 			}
 		}
@@ -435,17 +2724,83 @@ func notifyIfNeeded(state *State) {
 
 }
 
-// TODO: Make cross-platform
-func Notify(tab string) error {
-	osa, err := exec.LookPath("osascript")
+// Returns the items in current that are not in previous
+// This runs every frame for the headers, so the keys of previous are looked up
+// in a set instead of comparing every pair of items
+func newItems(previous, current []Item) []Item {
+	seen := make(map[string]bool, len(previous))
+	for _, item := range previous {
+		seen[itemKey(item)] = true
+	}
+	var added []Item
+	for _, item := range current {
+		if !seen[itemKey(item)] {
+			added = append(added, item)
+		}
+	}
+	return added
+}
+
+func notificationMessage(tab string, added []Item) string {
+	switch len(added) {
+	case 0:
+		return fmt.Sprintf("%s was updated", tab)
+	case 1:
+		return fmt.Sprintf("New in %s: %s", tab, added[0].Label())
+	default:
+		return fmt.Sprintf("%d new in %s, latest: %s", len(added), tab, added[0].Label())
+	}
+}
+
+// Send a desktop notification using whatever the platform provides
+// If there is no way to send notifications, the message is logged instead
+// Does not wait for the notification to be shown, since that can take a while
+// and this is called during a frame
+func Notify(title, message string) error {
+	cmd, err := notifyCommand(title, message)
 	if err != nil {
+		fmt.Printf("%s: %s\n", title, message)
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
 		return err
 	}
+	go cmd.Wait()
+	return nil
+}
 
-	msg := fmt.Sprintf("Something %s happend, lol?", tab)
-	script := fmt.Sprintf("display notification %q with title %q", msg, PROGRAM_NAME)
-	cmd := exec.Command(osa, "-e", script)
-	return cmd.Run()
+func notifyCommand(title, message string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		osa, err := exec.LookPath("osascript")
+		if err != nil {
+			return nil, err
+		}
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		return exec.Command(osa, "-e", script), nil
+	case "windows":
+		powershell, err := exec.LookPath("powershell")
+		if err != nil {
+			return nil, err
+		}
+		quote := func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(%s).Show($toast)`, quote(title), quote(message), quote(title))
+		return exec.Command(powershell, "-NoProfile", "-Command", script), nil
+	default:
+		notifySend, err := exec.LookPath("notify-send")
+		if err != nil {
+			return nil, err
+		}
+		return exec.Command(notifySend, title, message), nil
+	}
 }
 
 func drawRuler() {
@@ -453,32 +2808,254 @@ func drawRuler() {
 	rl.DrawRectangle(0, int32(RULER_Y), int32(width), 1, COLOR_RULER)
 }
 
+// Draw when the selected tab was last fetched, right below the ruler
+func drawLastUpdated(state State, font rl.Font, fontSize float32) {
+	fetchedAt := state.TabData[state.SelectedTab].FetchedAt
+	if fetchedAt.IsZero() {
+		return
+	}
+	text := fmt.Sprintf("updated %s", relativeTime(fetchedAt))
+	if d := time.Since(fetchedAt); d < time.Minute {
+		text = fmt.Sprintf("updated %ds ago", int(d.Seconds()))
+	}
+	textWidth := rl.MeasureTextEx(font, text, fontSize, 0).X
+	x := float32(rl.GetScreenWidth()-PAD_X) - textWidth
+	rl.DrawTextEx(font, text, rl.NewVector2(x, float32(RULER_Y+2)), fontSize, 0, COLOR_RULER)
+}
+
 func drawBody(state State, font rl.Font, fontSize float32) {
 	data := state.TabData[state.SelectedTab]
-	for i, d := range data.Items {
-		y := BODY_Y + i*(FONT_SIZE_BODY+5)
-		if i == state.TabDisplays[state.SelectedTab].SelectedItem {
-			textWidth := rl.MeasureText(d.Value, int32(FONT_SIZE_BODY))
+	if data.Err != nil && len(data.Items) == 0 {
+		text := fmt.Sprintf("Failed at %s: %s", data.FailedAt.Format(time.TimeOnly), data.Err.Error())
+		rl.DrawTextEx(font, text, rl.NewVector2(float32(PAD_X), float32(BODY_Y)), fontSize, 0, COLOR_ERROR)
+		return
+	}
+	if data.Loading && len(data.Items) == 0 {
+		rl.DrawTextEx(font, "Loading...", rl.NewVector2(float32(PAD_X), float32(BODY_Y)), fontSize, 0, COLOR_RULER)
+		return
+	}
+	tab := state.TabDisplays[state.SelectedTab]
+	items := state.visibleItems(state.SelectedTab)
+	end := min(len(items), tab.ScrollOffset+visibleRows(tab.DisplayMode))
+	maxWidth := float32(rl.GetScreenWidth() - 2*PAD_X)
+	// The sources are a column as wide as the widest source in the tab, but at
+	// most a third of the width
+	sourceWidth := float32(0)
+	for _, item := range items {
+		sourceWidth = max(sourceWidth, rl.MeasureTextEx(font, item.Source, fontSize, 0).X)
+	}
+	sourceWidth = min(sourceWidth, maxWidth/3)
+	valueX := float32(PAD_X)
+	if sourceWidth > 0 {
+		valueX += sourceWidth + float32(SOURCE_GAP)
+	}
+	for i := tab.ScrollOffset; i < end; i++ {
+		d := items[i]
+		y := BODY_Y + (i-tab.ScrollOffset)*rowHeight(tab.DisplayMode)
+		// The age goes in the right margin, so the value gets less room
+		// It is formatted every frame, so that it does not go stale between
+		// fetches
+		textMaxWidth := maxWidth
+		if !d.CreatedAt.IsZero() {
+			age := relativeTime(d.CreatedAt)
+			ageWidth := rl.MeasureTextEx(font, age, fontSize, 0).X
+			rl.DrawTextEx(font, age, rl.NewVector2(float32(PAD_X)+maxWidth-ageWidth, float32(y)), fontSize, 0, COLOR_RULER)
+			textMaxWidth -= ageWidth + float32(PAD_X)
+		}
+		text := truncate(d.Value, font, fontSize, textMaxWidth-(valueX-float32(PAD_X)))
+		if i == tab.SelectedItem {
+			textWidth := rl.MeasureTextEx(font, text, fontSize, 0).X
 			padding := float32(10)
-			rect := rl.NewRectangle(float32(PAD_X)-padding, float32(y), float32(textWidth)+2*padding, float32(FONT_SIZE_BODY))
+			rect := rl.NewRectangle(float32(PAD_X)-padding, float32(y), valueX-float32(PAD_X)+textWidth+2*padding, float32(FONT_SIZE_BODY))
 			rl.DrawRectangleRounded(rect, 1, 1, COLOR_SELECTED_ITEM)
 		}
-		rl.DrawTextEx(font, d.Value, rl.NewVector2(float32(PAD_X), float32(y)), fontSize, 0, COLOR_ITEM)
+		if tab.DisplayMode == DISPLAY_DETAILED {
+			details := d.URL
+			if !d.CreatedAt.IsZero() {
+				details = fmt.Sprintf("%s  %s", d.CreatedAt.Local().Format(time.DateTime), d.URL)
+			}
+			details = truncate(details, font, float32(FONT_SIZE_SMALL), maxWidth-(valueX-float32(PAD_X)))
+			rl.DrawTextEx(font, details, rl.NewVector2(valueX, float32(y+FONT_SIZE_BODY)), float32(FONT_SIZE_SMALL), 0, COLOR_RULER)
+		}
+		if d.Source != "" {
+			source := truncate(d.Source, font, fontSize, sourceWidth)
+			rl.DrawTextEx(font, source, rl.NewVector2(float32(PAD_X), float32(y)), fontSize, 0, COLOR_RULER)
+		}
+		color := COLOR_ITEM
+		if d.Color != (rl.Color{}) {
+			color = d.Color
+		}
+		rl.DrawTextEx(font, text, rl.NewVector2(valueX, float32(y)), fontSize, 0, color)
+	}
+}
+
+// Shortens text with "..." so that it is at most maxWidth wide
+func truncate(text string, font rl.Font, fontSize, maxWidth float32) string {
+	if rl.MeasureTextEx(font, text, fontSize, 0).X <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		truncated := string(runes) + "..."
+		if rl.MeasureTextEx(font, truncated, fontSize, 0).X <= maxWidth {
+			return truncated
+		}
+	}
+	return ""
+}
+
+// Returns the clickable area of the nth visible row in the body
+func getItemRect(row int, mode DisplayMode) rl.Rectangle {
+	padding := 10
+	y := BODY_Y + row*rowHeight(mode)
+	width := rl.GetScreenWidth() - 2*PAD_X + 2*padding
+	return rl.NewRectangle(float32(PAD_X-padding), float32(y), float32(width), float32(rowHeight(mode)-5))
+}
+
+// Detailed rows have room for the url below the value
+func rowHeight(mode DisplayMode) int {
+	if mode == DISPLAY_DETAILED {
+		return FONT_SIZE_BODY + FONT_SIZE_SMALL + 10
+	}
+	return FONT_SIZE_BODY + 5
+}
+
+// The number of items that fit between the ruler and the help text
+func visibleRows(mode DisplayMode) int {
+	bodyHeight := rl.GetScreenHeight() - HELP_Y_PADDING - BODY_Y
+	return max(1, bodyHeight/rowHeight(mode))
+}
+
+// Scroll the body of the selected tab so that the selected item is visible
+// Move the selection to the item that was selected in the last frame, in case
+// the items were updated since then, or keep it in range if the item is gone
+func followSelection(state *State) {
+	for _, tabID := range state.TabIDs {
+		tab := state.TabDisplays[tabID]
+		items := state.visibleItems(tabID)
+		moved := tab.SelectedItem >= len(items) || items[tab.SelectedItem].URL != tab.SelectedURL
+		if tab.SelectedURL != "" && moved {
+			if i := slices.IndexFunc(items, func(item Item) bool { return item.URL == tab.SelectedURL }); i >= 0 {
+				tab.SelectedItem = i
+			}
+		}
+		tab.SelectedItem = max(0, min(tab.SelectedItem, len(items)-1))
+		state.TabDisplays[tabID] = tab
+	}
+}
+
+func rememberSelection(state *State) {
+	for _, tabID := range state.TabIDs {
+		tab := state.TabDisplays[tabID]
+		items := state.visibleItems(tabID)
+		tab.SelectedURL = ""
+		if tab.SelectedItem < len(items) {
+			tab.SelectedURL = items[tab.SelectedItem].URL
+		}
+		state.TabDisplays[tabID] = tab
+	}
+}
+
+func scrollToSelection(state *State) {
+	tab := state.TabDisplays[state.SelectedTab]
+	rows := visibleRows(tab.DisplayMode)
+	nItems := len(state.visibleItems(state.SelectedTab))
+	if tab.SelectedItem < tab.ScrollOffset {
+		tab.ScrollOffset = tab.SelectedItem
+	} else if tab.SelectedItem >= tab.ScrollOffset+rows {
+		tab.ScrollOffset = tab.SelectedItem - rows + 1
+	}
+	// Don't leave empty rows at the bottom when the window grows
+	tab.ScrollOffset = max(0, min(tab.ScrollOffset, nItems-rows))
+	state.TabDisplays[state.SelectedTab] = tab
+}
+
+// Returns the name of the first key bound to an action
+func keyName(keybindings map[string][]Keybinding, action string) string {
+	if len(keybindings[action]) == 0 {
+		return "?"
+	}
+	return keybindings[action][0].String()
+}
+
+func drawHelp(state State, keybindings map[string][]Keybinding, font rl.Font, fontSize float32) {
+	move := fmt.Sprintf("%s/%s/%s/%s/%s/%s", keyName(keybindings, "left"), keyName(keybindings, "down"), keyName(keybindings, "up"), keyName(keybindings, "right"), keyName(keybindings, "top"), keyName(keybindings, "bottom"))
+	// Only tabs with a limit can load more
+	more := ""
+	if state.TabData[state.SelectedTab].MaxItems > 0 {
+		more = fmt.Sprintf(`<%s> MORE    `, keyName(keybindings, "more"))
+	}
+	text := fmt.Sprintf(`<%s, 1..%d> MOVE    <%s> OPEN    <%s> COPY    </> FILTER    <%s> SORT (%s)    <%s> VIEW (%s)    %s<%s> QUIT`, move, min(9, len(state.visibleTabs())), keyName(keybindings, "open"), keyName(keybindings, "copy"), keyName(keybindings, "sort"), state.TabDisplays[state.SelectedTab].SortMode, keyName(keybindings, "details"), state.TabDisplays[state.SelectedTab].DisplayMode, more, keyName(keybindings, "quit"))
+	if state.Silencing {
+		text = fmt.Sprintf(`Silence for %s_    <enter> SILENCE    <esc> CANCEL`, state.SilenceInput)
+		if _, err := time.ParseDuration(state.SilenceInput); err != nil {
+			text = fmt.Sprintf(`Silence for %s_ (like 2h)    <esc> CANCEL`, state.SilenceInput)
+		}
+	} else if state.ConfirmQuit {
+		text = fmt.Sprintf(`Press %s again to quit    <y> YES    <any> NO`, keyName(keybindings, "quit"))
+	} else if state.ConfirmOpenAll {
+		text = fmt.Sprintf(`Open %d items?    <y> YES    <any> NO`, len(uniqueURLs(state.visibleItems(state.SelectedTab))))
+	} else if state.Filtering {
+		text = fmt.Sprintf(`/%s_    <enter> DONE    <esc> CLEAR`, state.TabDisplays[state.SelectedTab].Filter)
+	} else if filter := state.TabDisplays[state.SelectedTab].Filter; filter != "" {
+		text = fmt.Sprintf(`/%s    </> EDIT    <esc> CLEAR`, filter)
+	}
+	// Break the text in two lines in narrow windows, and shrink the lines if
+	// they still don't fit
+	maxWidth := float32(rl.GetScreenWidth() - 2*PAD_X)
+	lines := []string{text}
+	if rl.MeasureTextEx(font, text, fontSize, 0).X > maxWidth {
+		sections := strings.Split(text, HELP_SEPARATOR)
+		half := (len(sections) + 1) / 2
+		lines = []string{strings.Join(sections[:half], HELP_SEPARATOR), strings.Join(sections[half:], HELP_SEPARATOR)}
+	}
+	y := float32(rl.GetScreenHeight() - HELP_Y_PADDING)
+	for _, line := range lines {
+		size := fontSize
+		if width := rl.MeasureTextEx(font, line, size, 0).X; width > maxWidth {
+			size = fontSize * maxWidth / width
+		}
+		textWidth := rl.MeasureTextEx(font, line, size, 0).X
+		x := (float32(rl.GetScreenWidth()) - textWidth) / 2
+		rect := rl.NewRectangle(x, y, textWidth, size)
+		rl.DrawRectangleRounded(rect, 1, 1, COLOR_HELP_BG)
+		rl.DrawTextEx(font, line, rl.NewVector2(x, y), size, 0, COLOR_HELP)
+		y += size
+	}
+}
+
+// Draw a spinner in the top right corner while any tab is being fetched
+func drawSpinner(state State, font rl.Font, fontSize float32) {
+	fetching := slices.ContainsFunc(state.TabIDs, func(tabID string) bool { return state.TabData[tabID].Fetching })
+	if !fetching {
+		return
 	}
+	frames := []rune(SPINNER_FRAMES)
+	frame := string(frames[time.Now().UnixMilli()/100%int64(len(frames))])
+	textWidth := rl.MeasureTextEx(font, frame, fontSize, 0).X
+	x := float32(rl.GetScreenWidth()) - (float32(PAD_X)+textWidth)/2
+	rl.DrawTextEx(font, frame, rl.NewVector2(x, float32(HEADER_Y)), fontSize, 0, COLOR_RULER)
 }
 
-func drawHelp(state State, font rl.Font, fontSize float32) {
-	text := fmt.Sprintf(`<hjkl, wasd, arrows, 1..%d> MOVE    <enter, space> OPEN    <q> QUIT`, len(state.TabIDs))
-	textWidth := rl.MeasureText(text, int32(FONT_SIZE_HELP))
-	x := (rl.GetScreenWidth() - int(textWidth)) / 2
-	y := rl.GetScreenHeight() - HELP_Y_PADDING
-	rect := rl.NewRectangle(float32(x), float32(y), float32(textWidth), float32(FONT_SIZE_HELP))
-	rl.DrawRectangleRounded(rect, 1, 1, COLOR_PINK_BG)
-	rl.DrawTextEx(font, text, rl.NewVector2(float32(x), float32(y)), fontSize, 0, COLOR_HELP)
+// Draw the remaining GitHub requests in the bottom right corner, once known
+func drawRateLimit(font rl.Font, fontSize float32) {
+	rateLimit, ok := github.GetRateLimit()
+	if !ok {
+		return
+	}
+	text := fmt.Sprintf("API: %d/%d", rateLimit.Remaining, rateLimit.Limit)
+	if rateLimit.Remaining == 0 {
+		text = fmt.Sprintf("%s, resets %s", text, rateLimit.Reset.Format(time.TimeOnly))
+	}
+	textWidth := rl.MeasureTextEx(font, text, fontSize, 0).X
+	x := float32(rl.GetScreenWidth()-PAD_X/4) - textWidth
+	y := float32(rl.GetScreenHeight()) - fontSize
+	rl.DrawTextEx(font, text, rl.NewVector2(x, y), fontSize, 0, COLOR_RULER)
 }
 
 func getHeaderRects(nHeaders int) []rl.Rectangle {
-	y := 10
+	y := HEADER_Y
 	width := rl.GetScreenWidth()
 	headerWidth := (width - 2*PAD_X) / nHeaders
 	headerHeight := FONT_SIZE_HEADER
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"daeshboard/internal/github"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Writes contents to a config file in a temporary directory and returns its path
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Could not write config: %s", err.Error())
+	}
+	return filename
+}
+
+func TestBuildConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  bool
+		// The repos as host/owner/name
+		wantRepos  []string
+		wantServer string
+	}{
+		{
+			name: "repos and alerts",
+			contents: `{
+				"repos": ["raysan5/raylib", "github.mycompany.com/internal/affairs"],
+				"alerts": {"server": "https://alertmanager.example.com/", "receiver": "team"}
+			}`,
+			wantRepos:  []string{"github.com/raysan5/raylib", "github.mycompany.com/internal/affairs"},
+			wantServer: "https://alertmanager.example.com",
+		},
+		{
+			name:      "organization",
+			contents:  `{"repos": ["raysan5/*"]}`,
+			wantRepos: []string{"github.com/raysan5/*"},
+		},
+		{
+			name:     "malformed json",
+			contents: `{"repos": ["raysan5/raylib"`,
+			wantErr:  true,
+		},
+		{
+			name:     "repo without a slash",
+			contents: `{"repos": ["raylib"]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "empty file",
+			contents: "",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_TOKEN", "")
+			config, err := buildConfig(writeConfig(t, tt.contents))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %s", err.Error())
+			}
+			var repos []string
+			for _, r := range config.Repos {
+				repos = append(repos, r.Host+"/"+r.String())
+			}
+			if !slices.Equal(repos, tt.wantRepos) {
+				t.Errorf("Got repos %v, want %v", repos, tt.wantRepos)
+			}
+			if config.Alerts.Server != tt.wantServer {
+				t.Errorf("Got alerts server %s, want %s", config.Alerts.Server, tt.wantServer)
+			}
+		})
+	}
+}
+
+func TestBuildConfigRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     time.Duration
+	}{
+		{name: "set", contents: `{"refreshInterval": "45s"}`, want: 45 * time.Second},
+		{name: "absent", contents: `{}`, want: DEFAULT_REFRESH_INTERVAL},
+		{name: "unparseable", contents: `{"refreshInterval": "often"}`, want: DEFAULT_REFRESH_INTERVAL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_TOKEN", "")
+			config, err := buildConfig(writeConfig(t, tt.contents))
+			if err != nil {
+				t.Fatalf("Got error: %s", err.Error())
+			}
+			if config.RefreshInterval != tt.want {
+				t.Errorf("Got refresh interval %s, want %s", config.RefreshInterval, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientUsesProxyFromEnvironment(t *testing.T) {
+	// The proxy variables are read once, by the first request that uses them,
+	// so no other test may make requests through newHTTPClient before this
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "")
+	transport := newHTTPClient(time.Second).Transport.(*http.Transport)
+	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	if err != nil {
+		t.Fatalf("Could not create request: %s", err.Error())
+	}
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Could not get the proxy: %s", err.Error())
+	}
+	if proxy == nil || proxy.String() != "http://proxy.example.com:3128" {
+		t.Errorf("Got proxy %v, want http://proxy.example.com:3128", proxy)
+	}
+}
+
+func TestUpdateTabWhileReading(t *testing.T) {
+	state := newState()
+	var fetches atomic.Int32
+	state.addTab("PRs", func(ctx context.Context) ([]Item, error) {
+		n := fetches.Add(1)
+		return []Item{{Value: fmt.Sprintf("#%d", n), URL: fmt.Sprintf("https://github.com/o/r/pull/%d", n)}}, nil
+	})
+	data := state.TabData["PRs"]
+	data.RefreshInterval = time.Millisecond
+	state.TabData["PRs"] = data
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		updateTab(ctx, &state, "PRs", 0)
+	}()
+	// Read like the render loop does, while the updater keeps writing
+	for fetches.Load() < 20 {
+		state.mu.Lock()
+		_ = state.TabData["PRs"].Items
+		_ = state.visibleItems("PRs")
+		state.markViewed("PRs")
+		state.mu.Unlock()
+		time.Sleep(100 * time.Microsecond)
+	}
+	cancel()
+	<-done
+
+	if items := state.visibleItems("PRs"); len(items) != 1 {
+		t.Errorf("Got %d items, want the 1 item of the last fetch", len(items))
+	}
+	if state.TabData["PRs"].Loading {
+		t.Errorf("The tab is still loading after %d fetches", fetches.Load())
+	}
+}
+
+// Returns a state with three tabs, where the first has ten items
+func navigationState() *State {
+	state := newState()
+	for _, title := range []string{"PRs", "Issues", "Alerts"} {
+		state.addTab(title, nil)
+	}
+	data := state.TabData["PRs"]
+	for i := range 10 {
+		data.Items = append(data.Items, Item{Value: fmt.Sprintf("#%d", i), URL: fmt.Sprintf("https://github.com/o/r/pull/%d", i)})
+	}
+	state.TabData["PRs"] = data
+	return &state
+}
+
+// Presses the keys in order, where a key is written like in the config, or
+// is a digit, or is "timeout" for waiting for the key after a digit
+func pressKeys(t *testing.T, state *State, keys []string, confirmQuit bool) {
+	t.Helper()
+	keybindings, err := parseKeybindings(nil)
+	if err != nil {
+		t.Fatalf("Could not parse the default keybindings: %s", err.Error())
+	}
+	for _, name := range keys {
+		var pressed Keybinding
+		switch {
+		case name == "timeout":
+			state.PendingCountAt = time.Now().Add(-2 * PENDING_COUNT_TIMEOUT)
+		case len(name) == 1 && name[0] >= '0' && name[0] <= '9':
+			pressed = Keybinding{Key: rl.KeyZero + int32(name[0]-'0')}
+		default:
+			var ok bool
+			if pressed, ok = parseKey(name); !ok {
+				t.Fatalf("Unknown key %s", name)
+			}
+		}
+		applyKey(state, pressed, keybindings, confirmQuit, 5)
+	}
+}
+
+func TestApplyKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        []string
+		confirmQuit bool
+		wantTab     string
+		wantItem    int
+		wantClose   bool
+	}{
+		{name: "right", keys: []string{"l"}, wantTab: "Issues"},
+		{name: "right and back", keys: []string{"l", "l", "h"}, wantTab: "Issues"},
+		{name: "left of the first tab", keys: []string{"h"}, wantTab: "PRs"},
+		{name: "right of the last tab", keys: []string{"l", "l", "l"}, wantTab: "Alerts"},
+		{name: "down and up", keys: []string{"j", "j", "k"}, wantTab: "PRs", wantItem: 1},
+		{name: "up from the top", keys: []string{"k"}, wantTab: "PRs"},
+		{name: "count before down", keys: []string{"5", "j"}, wantTab: "PRs", wantItem: 5},
+		{name: "count past the bottom", keys: []string{"1", "2", "j"}, wantTab: "PRs", wantItem: 9},
+		{name: "count before up", keys: []string{"G", "3", "k"}, wantTab: "PRs", wantItem: 6},
+		{name: "count before page down", keys: []string{"1", "ctrl+d"}, wantTab: "PRs", wantItem: 5},
+		{name: "bottom", keys: []string{"G"}, wantTab: "PRs", wantItem: 9},
+		{name: "bottom and top", keys: []string{"G", "g"}, wantTab: "PRs"},
+		{name: "digit switches tab after waiting", keys: []string{"3", "timeout"}, wantTab: "Alerts"},
+		{name: "digit switches tab before another key", keys: []string{"2", "l"}, wantTab: "Alerts"},
+		{name: "digit past the last tab", keys: []string{"7", "timeout"}, wantTab: "PRs"},
+		{name: "quit", keys: []string{"q"}, wantTab: "PRs", wantClose: true},
+		{name: "quit asks first", keys: []string{"q"}, confirmQuit: true, wantTab: "PRs"},
+		{name: "quit twice", keys: []string{"q", "q"}, confirmQuit: true, wantTab: "PRs", wantClose: true},
+		{name: "quit and yes", keys: []string{"q", "y"}, confirmQuit: true, wantTab: "PRs", wantClose: true},
+		{name: "quit and another key", keys: []string{"q", "j"}, confirmQuit: true, wantTab: "PRs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := navigationState()
+			pressKeys(t, state, tt.keys, tt.confirmQuit)
+			if state.SelectedTab != tt.wantTab {
+				t.Errorf("Got tab %s, want %s", state.SelectedTab, tt.wantTab)
+			}
+			if item := state.TabDisplays[state.SelectedTab].SelectedItem; item != tt.wantItem {
+				t.Errorf("Got item %d, want %d", item, tt.wantItem)
+			}
+			if state.ShouldClose != tt.wantClose {
+				t.Errorf("Got ShouldClose %t, want %t", state.ShouldClose, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestSharedGetterFetchesOnceForTabsFetchedTogether(t *testing.T) {
+	var fetches atomic.Int32
+	getItems := sharedGetter(func(ctx context.Context) ([]Item, error) {
+		fetches.Add(1)
+		// Long enough for the other tabs to ask while this is running
+		time.Sleep(50 * time.Millisecond)
+		return []Item{{Value: "#1", URL: "https://github.com/o/r/pull/1"}}, nil
+	})
+	ctx := github.WithMaxItems(context.Background(), 30)
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items, err := getItems(ctx)
+			if err != nil || len(items) != 1 {
+				t.Errorf("Got items %v and error %v, want the 1 item", items, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Fetched %d times for 3 tabs at once, want 1", n)
+	}
+
+	// A tab that has loaded more items does not get the items of the others
+	fetches.Store(0)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		getItems(ctx)
+	}()
+	getItems(github.WithMaxItems(context.Background(), 60))
+	wg.Wait()
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("Fetched %d times for 2 tabs with different limits, want 2", n)
+	}
+}